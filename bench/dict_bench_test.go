@@ -0,0 +1,80 @@
+package bench
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Alfex4936/kospell/internal/dictmatch"
+)
+
+// 10 000 synthetic dictionary words and a ~300-어절 text that contains some
+// of them with stray spaces inside.
+var (
+	dictWords = buildDictWords(10000)
+	dictText  = buildDictText(dictWords)
+)
+
+func buildDictWords(n int) []string {
+	syl := []rune("가나다라마바사아자차카타파하거너더러머버서어저처커터퍼허")
+	out := make([]string, n)
+	for i := range out {
+		r := []rune{syl[i%len(syl)], syl[(i/len(syl))%len(syl)], syl[(i/(len(syl)*len(syl)))%len(syl)], '솜'}
+		out[i] = string(r)
+	}
+	return out
+}
+
+func buildDictText(words []string) string {
+	var b strings.Builder
+	for i := 0; i < 300; i++ {
+		if i%10 == 0 {
+			w := []rune(words[i*31%len(words)])
+			b.WriteString(string(w[:2]) + " " + string(w[2:]))
+		} else {
+			b.WriteString("일반적인")
+		}
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// regexCanonicalize is the previous per-call approach: one regexp per word,
+// compiled on every call.
+func regexCanonicalize(s string, words []string) string {
+	for _, w := range words {
+		runes := []rune(strings.Join(strings.Fields(w), ""))
+		if len(runes) < 2 {
+			continue
+		}
+		var b strings.Builder
+		for i, r := range runes {
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			if i != len(runes)-1 {
+				b.WriteString(`\s*`)
+			}
+		}
+		s = regexp.MustCompile(b.String()).ReplaceAllString(s, w)
+	}
+	return s
+}
+
+func BenchmarkDictCanonicalizeRegex10k(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = regexCanonicalize(dictText, dictWords)
+	}
+}
+
+func BenchmarkDictCanonicalizeMatcher10k(b *testing.B) {
+	m := dictmatch.New(dictWords) // built once per Dict
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.Canonicalize(dictText, nil)
+	}
+}
+
+func BenchmarkDictMatcherBuild10k(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = dictmatch.New(dictWords)
+	}
+}
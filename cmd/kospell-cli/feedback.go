@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Alfex4936/kospell/internal/config"
+	"github.com/Alfex4936/kospell/internal/util"
+	"github.com/Alfex4936/kospell/kospell"
+)
+
+// defaultFeedbackFile is used when neither -file nor the config's
+// "feedback" key is set.
+const defaultFeedbackFile = ".kospell-feedback.jsonl"
+
+const feedbackUsage = `usage:
+  kospell-cli feedback accept -origin 목제솜틀기 -suggest "목제 솜틀기"
+  kospell-cli feedback reject -origin 목제솜틀기 -suggest "목제 솜틀기"
+  kospell-cli feedback report [-min-rejects 3] [-min-rate 0.8]
+  kospell-cli feedback report -promote brand -dict-store dicts/
+`
+
+// runFeedback implements the "feedback" subcommand and returns the exit code.
+func runFeedback(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, feedbackUsage)
+		return exitError
+	}
+	cmd, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("feedback "+cmd, flag.ContinueOnError)
+	configPath := fs.String("config", "", "config file (default: nearest .kospell.yaml walking up from the working directory)")
+	file := fs.String("file", defaultFeedbackFile, "feedback log (JSON lines)")
+	origin := fs.String("origin", "", "text the checker flagged (accept/reject)")
+	suggest := fs.String("suggest", "", "suggestion that was accepted or rejected (accept/reject)")
+	minRejects := fs.Int("min-rejects", kospell.DefaultMinRejects, "rejections needed before a term is a candidate (report)")
+	minRate := fs.Float64("min-rate", kospell.DefaultMinRejectRate, "share of decisions that must be rejections (report)")
+	promote := fs.String("promote", "", "add the candidates to this named dictionary (report)")
+	dictStore := fs.String("dict-store", "", "named dictionary directory used by -promote, same layout as kospell-server -dict-store")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	cfg, err := config.Discover(*configPath)
+	must(err)
+	config.Apply(config.SetFlags(fs), "file", "", file, cfg.Feedback)
+	fb := kospell.OpenFeedbackLog(*file)
+
+	switch cmd {
+	case kospell.FeedbackAccept, kospell.FeedbackReject:
+		must(fb.Record(kospell.Feedback{Origin: *origin, Suggest: *suggest, Decision: cmd}))
+		return exitClean
+
+	case "report":
+		entries, err := fb.Entries()
+		must(err)
+		cands := kospell.FeedbackReport(entries, kospell.FeedbackReportOptions{
+			MinRejects:    *minRejects,
+			MinRejectRate: *minRate,
+		})
+		if cands == nil {
+			cands = []kospell.DictCandidate{}
+		}
+
+		if *promote != "" {
+			if *dictStore == "" {
+				must(fmt.Errorf("-promote requires -dict-store"))
+			}
+			store, err := kospell.OpenDictStore(*dictStore)
+			must(err)
+			_, err = kospell.PromoteCandidates(store, *promote, cands)
+			must(err)
+			fmt.Fprintf(os.Stderr, "kospell-cli: promoted %d term(s) into %q\n", len(cands), *promote)
+		}
+
+		out, err := util.MarshalNoEscape(cands, true)
+		must(err)
+		fmt.Println(string(out))
+		return exitClean
+
+	default:
+		fmt.Fprintf(os.Stderr, "kospell-cli: unknown feedback command %q\n%s", cmd, feedbackUsage)
+		return exitError
+	}
+}
@@ -1,12 +1,26 @@
-// Command kospell-cli pipes stdin (or a file) through kospell.Check
-// and prints the pretty-printed JSON result.
+// Command kospell-cli pipes stdin (or files) through kospell.Check
+// and prints the pretty-printed JSON result or a CI report.
+//
+// Exit codes:
+//
+//	0  no findings above the threshold
+//	1  more than -max-errors findings of the -fail-on types
+//	2  operational error (bad flags, I/O, upstream failure)
 //
 // Usage:
 //
 //	echo "너는나와 ..." | kospell-cli
+//	kospell-cli -f text.txt
+//	kospell-cli -format sarif docs/ README.md > kospell.sarif
+//	kospell-cli -quiet -max-errors 3 -fail-on spelling docs/
+//	kospell-cli -config ci/.kospell.yaml docs/
 //	kospell-cli -mode hunspell -dict-dir /path/to/hunspell-dict-ko -lang ko
 //	kospell-cli -mode hanspell
 //	kospell-cli -mode openai -llm-key $OPENAI_API_KEY
+//	kospell-cli -mode openai -llm-provider anthropic -llm-key $ANTHROPIC_API_KEY
+//	kospell-cli -mode hanspell -llm-explain -llm-key $OPENAI_API_KEY
+//	kospell-cli feedback reject -origin 목제솜틀기 -suggest "목제 솜틀기"
+//	kospell-cli feedback report -promote brand -dict-store dicts/
 package main
 
 import (
@@ -14,101 +28,398 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/Alfex4936/kospell/internal/config"
 	internalhanspell "github.com/Alfex4936/kospell/internal/hanspell"
 	internalllm "github.com/Alfex4936/kospell/internal/llm"
 	"github.com/Alfex4936/kospell/internal/local"
 	"github.com/Alfex4936/kospell/internal/model"
+	"github.com/Alfex4936/kospell/internal/report"
 	"github.com/Alfex4936/kospell/internal/util"
 	"github.com/Alfex4936/kospell/kospell"
 )
 
+const stdinName = "<stdin>"
+
+const (
+	exitClean    = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+type checkFunc func(ctx context.Context, text string) (*model.Result, error)
+
+// fileResult is the JSON shape used when more than one input is checked.
+type fileResult struct {
+	Path   string        `json:"path"`
+	Result *model.Result `json:"result"`
+}
+
 func main() {
-	file := flag.String("f", "", "file to read instead of stdin")
-	dict := flag.String("d", "", "user dictionary JSON file (optional)")
-	timeout := flag.Duration("t", 30*time.Second, "overall timeout")
+	if len(os.Args) > 1 && os.Args[1] == "feedback" {
+		os.Exit(runFeedback(os.Args[2:]))
+	}
+
+	configPath := flag.String("config", "", "config file (default: nearest .kospell.yaml walking up from the working directory)")
+	file := flag.String("f", "", "file or directory to read instead of stdin")
+	dict := flag.String("d", "", "comma-separated user dictionary JSON files (optional)")
+	timeout := flag.Duration("t", 30*time.Second, "timeout per input")
 	mode := flag.String("mode", "nara", "backend: nara | hunspell | hanspell | openai")
+	format := flag.String("format", "json", "output format: json | sarif | checkstyle | junit")
+	errorTypes := flag.String("error-types", "", "comma-separated error types to report (default: all)")
+	maskFlag := flag.String("mask", "url,email,code,mention", "comma-separated span categories hidden from the backend: url,email,code,path,relpath,hashtag,mention,number | all | none")
+	exts := flag.String("ext", ".txt,.md", "comma-separated file extensions to check when walking directories")
+	maxErrors := flag.Int("max-errors", 0, "exit 1 when more findings than this are reported (-1 never fails)")
+	failOn := flag.String("fail-on", "", "comma-separated error types counted against -max-errors (default: all reported)")
+	quiet := flag.Bool("quiet", false, "print nothing; only set the exit code")
 	// hunspell flags
 	dictDir := flag.String("dict-dir", "", "hunspell dictionary directory (hunspell mode)")
 	lang := flag.String("lang", "ko", "hunspell dictionary name (hunspell mode)")
 	// openai flags
-	llmKey := flag.String("llm-key", os.Getenv("OPENAI_API_KEY"), "OpenAI API key (openai mode)")
-	llmModel := flag.String("llm-model", internalllm.DefaultModel, "LLM model name")
-	llmURL := flag.String("llm-url", internalllm.DefaultBaseURL, "OpenAI-compatible base URL")
+	llmProvider := flag.String("llm-provider", internalllm.ProviderOpenAI, "LLM API: "+strings.Join(internalllm.Providers(), " | ")+" (openai mode)")
+	llmKey := flag.String("llm-key", os.Getenv("OPENAI_API_KEY"), "LLM API key (openai mode; ANTHROPIC_API_KEY is used for -llm-provider anthropic)")
+	llmModel := flag.String("llm-model", "", "LLM model name (default: per provider, "+internalllm.DefaultModel+" for openai)")
+	llmURL := flag.String("llm-url", "", "LLM API base URL (default: per provider, "+internalllm.DefaultBaseURL+" for openai)")
+	llmChunkTokens := flag.Int("llm-chunk-tokens", kospell.LLMChunking.Size, "estimated tokens per LLM prompt; longer text is split by sentence")
+	llmChunkOverlap := flag.Int("llm-chunk-overlap", kospell.LLMChunking.Overlap, "tokens of preceding text repeated as context in each LLM prompt")
+	llmPrompts := flag.String("llm-prompts", "", "directory of <name>.tmpl prompt templates (and <name>.examples.json)")
+	llmPrompt := flag.String("llm-prompt", "", "prompt template name from -llm-prompts (default: built-in)")
+	llmPrices := flag.String("llm-prices", "", "per-model prices in USD per million tokens, e.g. gpt-5-mini=0.25/2 (input/output); fills meta.usage.cost")
+	llmExplain := flag.Bool("llm-explain", false, "have the LLM explain nara/hunspell/hanspell corrections whose help is empty or too short")
+	llmVerify := flag.Bool("llm-verify", false, "have the LLM judge nara/hunspell/hanspell corrections in context and suppress false positives")
 	flag.Parse()
 
-	var r io.Reader = os.Stdin
-	if *file != "" {
-		f, err := os.Open(*file)
+	cfg, err := config.Discover(*configPath)
+	must(err)
+	set := config.SetFlags(flag.CommandLine)
+	config.Apply(set, "mode", "", mode, cfg.Backend)
+	config.Apply(set, "format", "", format, cfg.Format)
+	config.Apply(set, "error-types", "", errorTypes, strings.Join(cfg.ErrorTypes, ","))
+	config.Apply(set, "mask", "", maskFlag, strings.Join(cfg.Mask, ","))
+	config.Apply(set, "d", "", dict, strings.Join(cfg.Dicts, ","))
+	config.Apply(set, "dict-dir", "", dictDir, cfg.Hunspell.DictDir)
+	config.Apply(set, "lang", "", lang, cfg.Hunspell.Lang)
+	config.Apply(set, "llm-provider", "", llmProvider, cfg.OpenAI.Provider)
+	config.Apply(set, "llm-model", "", llmModel, cfg.OpenAI.Model)
+	config.Apply(set, "llm-url", "", llmURL, cfg.OpenAI.BaseURL)
+	config.Apply(set, "llm-prompts", "", llmPrompts, cfg.OpenAI.Prompts)
+	config.Apply(set, "llm-prompt", "", llmPrompt, cfg.OpenAI.Prompt)
+	if !set["llm-explain"] && cfg.OpenAI.Explain {
+		*llmExplain = true
+	}
+	if !set["llm-verify"] && cfg.OpenAI.Verify {
+		*llmVerify = true
+	}
+	if !set["llm-chunk-tokens"] && cfg.OpenAI.ChunkTokens > 0 {
+		*llmChunkTokens = cfg.OpenAI.ChunkTokens
+	}
+	if !set["llm-chunk-overlap"] && cfg.OpenAI.ChunkOverlap > 0 {
+		*llmChunkOverlap = cfg.OpenAI.ChunkOverlap
+	}
+	if !set["t"] && cfg.Timeout != "" {
+		*timeout, err = time.ParseDuration(cfg.Timeout)
 		must(err)
-		defer f.Close()
-		r = f
 	}
 
-	data, err := io.ReadAll(r)
-	must(err)
+	writer, ok := report.Writers[*format]
+	if !ok && *format != "json" {
+		fmt.Fprintf(os.Stderr, "kospell-cli: unknown format %q (allowed: json, sarif, checkstyle, junit)\n", *format)
+		os.Exit(exitError)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
-	defer cancel()
+	failTypes, err := parseFailOn(splitList(*failOn))
+	must(err)
+	maskOpts, err := kospell.ParseMaskOptions(splitList(*maskFlag))
+	must(err)
 
-	text := string(data)
+	paths := flag.Args()
+	if *file != "" {
+		paths = append([]string{*file}, paths...)
+	}
+	inputs, err := collectInputs(paths, splitList(*exts), cfg)
+	must(err)
 
-	var d *kospell.Dict
-	if *dict != "" {
-		d, err = kospell.LoadDict(*dict)
+	// Dictionaries are re-checked before every input, so edits made during
+	// a long directory scan apply to the remaining files.
+	var dicts *kospell.DictWatcher
+	if dictPaths := splitList(*dict); len(dictPaths) > 0 {
+		dicts, err = kospell.WatchDicts(dictPaths...)
 		must(err)
 	}
+	var d *kospell.Dict
+
+	// The LLM serves openai mode and the explain and verify stages of the
+	// others.
+	newLLM := func() (c *internalllm.Checker) {
+		var err error
+		if *llmKey == "" && *llmProvider == internalllm.ProviderAnthropic {
+			*llmKey = os.Getenv("ANTHROPIC_API_KEY")
+		}
+		if *llmKey == "" && internalllm.NeedsKey(*llmProvider) {
+			fmt.Fprintln(os.Stderr, "kospell-cli: openai mode, -llm-explain and -llm-verify require -llm-key or OPENAI_API_KEY")
+			os.Exit(exitError)
+		}
+		var prompts internalllm.Prompts
+		if *llmPrompts != "" {
+			prompts, err = internalllm.LoadPrompts(*llmPrompts)
+			must(err)
+		}
+		p, perr := prompts.Lookup(*llmPrompt)
+		must(perr)
+		base, cerr := internalllm.NewFor(*llmProvider, *llmKey, *llmModel, *llmURL)
+		must(cerr)
+		c = base.WithPrompt(p)
+		prices := internalllm.Prices{}
+		for name, p := range cfg.OpenAI.Prices {
+			prices[name] = internalllm.Price{Input: p.Input, Output: p.Output}
+		}
+		if *llmPrices != "" {
+			prices, err = internalllm.ParsePrices(*llmPrices)
+			must(err)
+		}
+		c.Meter().SetPrices(prices)
+		return c
+	}
 
-	var res *model.Result
+	var check checkFunc
+	var reviewer *internalllm.Checker // explain and verify stages
 
 	switch *mode {
 	case "hunspell":
 		h, herr := local.New(*dictDir, *lang)
 		must(herr)
-		if d != nil {
-			res, err = kospell.CheckLocalWithDict(ctx, text, h, d)
-		} else {
-			res, err = kospell.CheckLocal(ctx, text, h)
+		check = func(ctx context.Context, text string) (*model.Result, error) {
+			if d != nil {
+				return kospell.CheckLocalWithDict(ctx, text, h, d)
+			}
+			return kospell.CheckLocal(ctx, text, h)
 		}
 
 	case "hanspell", "naver":
 		h := internalhanspell.New()
-		if d != nil {
-			res, err = kospell.CheckHanspellWithDict(ctx, text, h, d)
-		} else {
-			res, err = kospell.CheckHanspell(ctx, text, h)
+		check = func(ctx context.Context, text string) (*model.Result, error) {
+			if d != nil {
+				return kospell.CheckHanspellWithDict(ctx, text, h, d)
+			}
+			return kospell.CheckHanspell(ctx, text, h)
 		}
 
 	case "openai":
-		if *llmKey == "" {
-			fmt.Fprintln(os.Stderr, "kospell-cli: openai mode requires -llm-key or OPENAI_API_KEY")
-			os.Exit(1)
-		}
-		c := internalllm.New(*llmKey, *llmModel, *llmURL)
-		if d != nil {
-			res, err = kospell.CheckLLMWithDict(ctx, text, c, d)
-		} else {
-			res, err = kospell.CheckLLM(ctx, text, c, nil)
+		c := newLLM()
+		kospell.LLMChunking.Size = *llmChunkTokens
+		kospell.LLMChunking.Overlap = *llmChunkOverlap
+		check = func(ctx context.Context, text string) (*model.Result, error) {
+			if d != nil {
+				return kospell.CheckLLMWithDict(ctx, text, c, d)
+			}
+			return kospell.CheckLLM(ctx, text, c, nil)
 		}
 
 	default: // nara
-		if d != nil {
-			res, err = kospell.CheckWithDict(ctx, text, d)
+		check = func(ctx context.Context, text string) (*model.Result, error) {
+			if d != nil {
+				return kospell.CheckWithDict(ctx, text, d)
+			}
+			return kospell.Check(ctx, text)
+		}
+	}
+	if (*llmExplain || *llmVerify) && *mode != "openai" {
+		reviewer = newLLM()
+	}
+
+	files := make([]report.File, 0, len(inputs))
+	for _, path := range inputs {
+		data, err := readInput(path)
+		must(err)
+
+		if dicts != nil {
+			if _, err := dicts.Reload(); err != nil {
+				fmt.Fprintln(os.Stderr, "kospell-cli: dictionary reload failed, keeping last good version:", err)
+			}
+			d = dicts.Dict()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		res, err := kospell.CheckMasked(ctx, string(data), maskOpts, check)
+		if err != nil {
+			cancel()
+			must(fmt.Errorf("%s: %w", path, err))
+		}
+		if *errorTypes != "" {
+			must(kospell.FilterByErrorTypes(res, splitList(*errorTypes), d))
 		} else {
-			res, err = kospell.Check(ctx, text)
+			kospell.ClassifyErrorTypes(res)
+		}
+		if reviewer != nil && *llmVerify {
+			if err := kospell.Verify(ctx, res, reviewer, d); err != nil {
+				fmt.Fprintf(os.Stderr, "kospell-cli: %s: verify failed, keeping all corrections: %v\n", path, err)
+			}
 		}
+		if reviewer != nil && *llmExplain {
+			if err := kospell.Explain(ctx, res, reviewer); err != nil {
+				fmt.Fprintf(os.Stderr, "kospell-cli: %s: explain failed, keeping backend help: %v\n", path, err)
+			}
+		}
+		cancel()
+
+		files = append(files, report.File{Path: path, Result: res})
 	}
-	must(err)
 
-	out, _ := util.MarshalNoEscape(res, true)
-	fmt.Println(string(out))
+	if !*quiet {
+		must(writeOutput(writer, files, len(paths) == 0 || (len(paths) == 1 && len(inputs) == 1 && inputs[0] == paths[0])))
+	}
+
+	found := countFindings(files, failTypes)
+	if *maxErrors >= 0 && found > *maxErrors {
+		if !*quiet {
+			fmt.Fprintf(os.Stderr, "kospell-cli: %d finding(s), more than -max-errors %d\n", found, *maxErrors)
+		}
+		os.Exit(exitFindings)
+	}
+}
+
+// writeOutput prints files with writer, or as JSON when writer is nil.
+// A single file or stdin keeps the plain Result shape; anything that
+// expanded to several inputs is printed as a list of {path, result}.
+func writeOutput(writer report.Writer, files []report.File, single bool) error {
+	if writer != nil {
+		return writer(os.Stdout, files)
+	}
+
+	var v any
+	if single && len(files) == 1 {
+		v = files[0].Result
+	} else {
+		out := make([]fileResult, len(files))
+		for i, f := range files {
+			out[i] = fileResult{Path: f.Path, Result: f.Result}
+		}
+		v = out
+	}
+	out, err := util.MarshalNoEscape(v, true)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(out))
+	return err
+}
+
+// parseFailOn normalizes the -fail-on list. A nil set counts every finding.
+func parseFailOn(types []string) (map[string]struct{}, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+	set := make(map[string]struct{}, len(types))
+	for _, raw := range types {
+		t, ok := kospell.NormalizeErrorType(raw)
+		if !ok {
+			return nil, fmt.Errorf("invalid -fail-on type: %q", raw)
+		}
+		set[t] = struct{}{}
+	}
+	return set, nil
+}
+
+// countFindings counts corrections across files whose ErrorType is in types.
+func countFindings(files []report.File, types map[string]struct{}) int {
+	n := 0
+	for _, f := range files {
+		if f.Result == nil {
+			continue
+		}
+		for _, ch := range f.Result.Corrections {
+			for _, item := range ch.Items {
+				if types != nil {
+					if _, ok := types[item.ErrorType]; !ok {
+						continue
+					}
+				}
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// collectInputs expands directories into the files below them whose
+// extension is listed in exts and that cfg does not ignore. No paths means
+// stdin. Files named explicitly are always checked.
+func collectInputs(paths, exts []string, cfg *config.Config) ([]string, error) {
+	if len(paths) == 0 {
+		return []string{stdinName}, nil
+	}
+
+	var out []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			out = append(out, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, e fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if e.IsDir() {
+				if path != p && (strings.HasPrefix(e.Name(), ".") || cfg.Ignored(path)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if hasExt(path, exts) && !cfg.Ignored(path) {
+				out = append(out, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == stdinName {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func hasExt(path string, exts []string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range exts {
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		if strings.EqualFold(ext, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
 }
 
 func must(err error) {
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "kospell-cli:", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 }
@@ -6,15 +6,27 @@
 //	kospell-server -p 8080 -mode hunspell -dict /path/to/ko-dict -lang ko
 //	kospell-server -p 8080 -mode hanspell
 //	kospell-server -p 8080 -mode openai -llm-key $OPENAI_API_KEY
+//	kospell-server -p 8080 -mode openai -llm-provider ollama -llm-model qwen2.5
+//	kospell-server -p 8080 -mode hanspell -llm-explain -llm-key $OPENAI_API_KEY
+//	kospell-server -p 8080 -mode nara -llm-verify -llm-provider ollama
+//	kospell-server -config /etc/kospell/.kospell.yaml
+//
+// Settings are resolved as: flags > environment variables > config file
+// (-config, KOSPELL_CONFIG, or the nearest .kospell.yaml) > defaults.
 package main
 
 import (
+	"cmp"
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/Alfex4936/kospell/internal/config"
 	internalhanspell "github.com/Alfex4936/kospell/internal/hanspell"
 	internalllm "github.com/Alfex4936/kospell/internal/llm"
 	"github.com/Alfex4936/kospell/internal/local"
@@ -30,12 +42,84 @@ func main() {
 	lang := flag.String("lang", envOr("DICT_LANG", "ko"), "hunspell dictionary name (hunspell mode)")
 
 	// openai flags
-	llmKey := flag.String("llm-key", envOr("OPENAI_API_KEY", ""), "OpenAI API key (openai mode)")
-	llmModel := flag.String("llm-model", envOr("LLM_MODEL", internalllm.DefaultModel), "LLM model name")
-	llmURL := flag.String("llm-url", envOr("LLM_BASE_URL", internalllm.DefaultBaseURL), "OpenAI-compatible base URL")
+	llmProvider := flag.String("llm-provider", envOr("LLM_PROVIDER", internalllm.ProviderOpenAI), "LLM API: "+strings.Join(internalllm.Providers(), " | ")+" (openai mode)")
+	llmKey := flag.String("llm-key", envOr("OPENAI_API_KEY", ""), "LLM API key (openai mode; ANTHROPIC_API_KEY is used for -llm-provider anthropic)")
+	llmModel := flag.String("llm-model", envOr("LLM_MODEL", ""), "LLM model name (default: per provider, "+internalllm.DefaultModel+" for openai)")
+	llmURL := flag.String("llm-url", envOr("LLM_BASE_URL", ""), "LLM API base URL (default: per provider, "+internalllm.DefaultBaseURL+" for openai)")
+	llmChunkTokens := flag.Int("llm-chunk-tokens", envIntOr("LLM_CHUNK_TOKENS", kospell.LLMChunking.Size), "estimated tokens per LLM prompt; longer text is split by sentence")
+	llmChunkOverlap := flag.Int("llm-chunk-overlap", envIntOr("LLM_CHUNK_OVERLAP", kospell.LLMChunking.Overlap), "tokens of preceding text repeated as context in each LLM prompt")
+	llmPrompts := flag.String("llm-prompts", envOr("LLM_PROMPTS", ""), "directory of <name>.tmpl prompt templates (and <name>.examples.json) requests can select with \"prompt\"")
+	llmPrompt := flag.String("llm-prompt", envOr("LLM_PROMPT", ""), "default prompt template name from -llm-prompts (default: built-in)")
+	llmPrices := flag.String("llm-prices", envOr("LLM_PRICES", ""), "per-model prices in USD per million tokens, e.g. gpt-5-mini=0.25/2 (input/output)")
+	llmDailyTokens := flag.Int("llm-daily-tokens", envIntOr("LLM_DAILY_TOKENS", 0), "per-day LLM token budget (0: unlimited)")
+	llmBudgetFallback := flag.String("llm-budget-fallback", envOr("LLM_BUDGET_FALLBACK", ""), "backend (nara | hanspell) serving openai requests once the daily budget is spent (default: reject with 429)")
+	llmExplain := flag.Bool("llm-explain", envBoolOr("LLM_EXPLAIN", false), "have the LLM explain nara/hunspell/hanspell corrections whose help is empty or too short (any mode)")
+	llmVerify := flag.Bool("llm-verify", envBoolOr("LLM_VERIFY", false), "have the LLM judge nara/hunspell/hanspell corrections in context and suppress false positives (any mode)")
+
+	userDict := flag.String("user-dict", envOr("USER_DICT", ""), "comma-separated user dictionary JSON files applied to every request (hot-reloaded)")
+	dictStore := flag.String("dict-store", envOr("DICT_STORE_DIR", ""), "directory for named dictionaries served at /v1/dicts (disabled if empty)")
+	feedbackFile := flag.String("feedback-file", envOr("FEEDBACK_FILE", ""), "JSON-lines file recording /v1/feedback decisions (disabled if empty)")
+	configPath := flag.String("config", envOr("KOSPELL_CONFIG", ""), "config file (default: nearest .kospell.yaml walking up from the working directory)")
 
 	flag.Parse()
 
+	cfg, err := config.Discover(*configPath)
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	set := config.SetFlags(flag.CommandLine)
+	config.Apply(set, "mode", "MODE", mode, cfg.Backend)
+	config.Apply(set, "feedback-file", "FEEDBACK_FILE", feedbackFile, cfg.Feedback)
+	config.Apply(set, "dict", "DICT_DIR", dictDir, cfg.Hunspell.DictDir)
+	config.Apply(set, "lang", "DICT_LANG", lang, cfg.Hunspell.Lang)
+	config.Apply(set, "llm-provider", "LLM_PROVIDER", llmProvider, cfg.OpenAI.Provider)
+	config.Apply(set, "llm-model", "LLM_MODEL", llmModel, cfg.OpenAI.Model)
+	config.Apply(set, "llm-url", "LLM_BASE_URL", llmURL, cfg.OpenAI.BaseURL)
+	config.Apply(set, "llm-prompts", "LLM_PROMPTS", llmPrompts, cfg.OpenAI.Prompts)
+	config.Apply(set, "llm-prompt", "LLM_PROMPT", llmPrompt, cfg.OpenAI.Prompt)
+	config.Apply(set, "llm-budget-fallback", "LLM_BUDGET_FALLBACK", llmBudgetFallback, cfg.OpenAI.BudgetFallback)
+	if !set["llm-chunk-tokens"] && os.Getenv("LLM_CHUNK_TOKENS") == "" && cfg.OpenAI.ChunkTokens > 0 {
+		*llmChunkTokens = cfg.OpenAI.ChunkTokens
+	}
+	if !set["llm-chunk-overlap"] && os.Getenv("LLM_CHUNK_OVERLAP") == "" && cfg.OpenAI.ChunkOverlap > 0 {
+		*llmChunkOverlap = cfg.OpenAI.ChunkOverlap
+	}
+	if !set["llm-explain"] && os.Getenv("LLM_EXPLAIN") == "" && cfg.OpenAI.Explain {
+		*llmExplain = true
+	}
+	if !set["llm-verify"] && os.Getenv("LLM_VERIFY") == "" && cfg.OpenAI.Verify {
+		*llmVerify = true
+	}
+	if !set["llm-daily-tokens"] && os.Getenv("LLM_DAILY_TOKENS") == "" && cfg.OpenAI.DailyTokens > 0 {
+		*llmDailyTokens = cfg.OpenAI.DailyTokens
+	}
+
+	dictFiles := splitList(*userDict)
+	if !set["user-dict"] && os.Getenv("USER_DICT") == "" {
+		dictFiles = cfg.Dicts
+	}
+	if len(dictFiles) > 0 {
+		watcher, err := kospell.WatchDicts(dictFiles...)
+		if err != nil {
+			log.Fatalf("load dictionary: %v", err)
+		}
+		kospell.DefaultDicts = watcher
+		go watcher.Run(context.Background(), kospell.DictWatchInterval)
+		log.Printf("   dicts   : %d file(s), %d word(s), hot reload on\n", len(dictFiles), len(watcher.Dict().Words))
+	}
+	for _, t := range cfg.ErrorTypes {
+		if _, ok := kospell.NormalizeErrorType(t); !ok {
+			log.Fatalf("config: invalid error type %q", t)
+		}
+	}
+	kospell.DefaultErrorTypes = cfg.ErrorTypes
+	if len(cfg.Mask) > 0 {
+		kospell.DefaultMask, err = kospell.ParseMaskOptions(cfg.Mask)
+		if err != nil {
+			log.Fatalf("config: %v", err)
+		}
+	}
+
 	switch *mode {
 	case "hunspell":
 		h, err := local.New(*dictDir, *lang)
@@ -52,19 +136,109 @@ func main() {
 		log.Printf("   backend : hanspell (naver spell-check API)\n")
 
 	case "openai":
-		if *llmKey == "" {
-			log.Fatal("openai mode requires -llm-key or OPENAI_API_KEY env var")
-		}
 		kospell.Mode = "openai"
-		kospell.LLMChecker = internalllm.New(*llmKey, *llmModel, *llmURL)
-		log.Printf("   backend : openai (model=%s url=%s)\n", *llmModel, *llmURL)
 
 	default:
 		kospell.Mode = "nara"
 		log.Printf("   backend : nara (nara-speller API)\n")
 	}
 
+	// The LLM serves the openai backend and the explain and verify stages
+	// of the others.
+	if kospell.Mode == "openai" || *llmExplain || *llmVerify {
+		if *llmKey == "" && *llmProvider == internalllm.ProviderAnthropic {
+			*llmKey = os.Getenv("ANTHROPIC_API_KEY")
+		}
+		if *llmKey == "" && internalllm.NeedsKey(*llmProvider) {
+			log.Fatal("openai mode, -llm-explain and -llm-verify require -llm-key or OPENAI_API_KEY env var")
+		}
+		c, err := internalllm.NewFor(*llmProvider, *llmKey, *llmModel, *llmURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Only openai mode serves the openai backend; the stages get the
+		// client on their own, so -llm-explain alone never enables LLM checks.
+		if kospell.Mode == "openai" {
+			kospell.LLMChecker = c
+			log.Printf("   backend : openai (provider=%s model=%s url=%s chunk=%d tokens)\n", c.Provider(), c.Model(), c.BaseURL(), *llmChunkTokens)
+		} else {
+			log.Printf("   llm     : provider=%s model=%s url=%s\n", c.Provider(), c.Model(), c.BaseURL())
+		}
+		if *llmExplain || *llmVerify {
+			kospell.LLMStages = c
+		}
+		kospell.LLMChunking.Size = *llmChunkTokens
+		kospell.LLMChunking.Overlap = *llmChunkOverlap
+		if kospell.Mode == "openai" && *llmPrompts != "" {
+			prompts, err := internalllm.LoadPrompts(*llmPrompts)
+			if err != nil {
+				log.Fatalf("llm prompts: %v", err)
+			}
+			kospell.LLMPrompts = prompts
+		}
+		if kospell.Mode == "openai" && (*llmPrompts != "" || *llmPrompt != "") {
+			p, err := kospell.LLMPrompts.Lookup(*llmPrompt)
+			if err != nil {
+				log.Fatalf("llm prompt: %v", err)
+			}
+			kospell.LLMChecker = kospell.LLMChecker.WithPrompt(p)
+			log.Printf("   prompt  : %s (available: %s)\n", p.Name, strings.Join(kospell.LLMPrompts.Names(), ", "))
+		}
+		prices := internalllm.Prices{}
+		for name, p := range cfg.OpenAI.Prices {
+			prices[name] = internalllm.Price{Input: p.Input, Output: p.Output}
+		}
+		if *llmPrices != "" {
+			if prices, err = internalllm.ParsePrices(*llmPrices); err != nil {
+				log.Fatal(err)
+			}
+		}
+		meter := c.Meter() // shared with the prompt's copy
+		meter.SetPrices(prices)
+		meter.SetDailyBudget(*llmDailyTokens)
+		switch *llmBudgetFallback {
+		case "", "nara", "hanspell":
+			kospell.LLMBudgetFallback = *llmBudgetFallback
+		default:
+			log.Fatalf("-llm-budget-fallback: want nara or hanspell, got %q", *llmBudgetFallback)
+		}
+		if *llmDailyTokens > 0 {
+			log.Printf("   budget  : %d tokens/day (fallback: %s)\n", *llmDailyTokens, cmp.Or(*llmBudgetFallback, "reject"))
+		}
+		kospell.LLMExplain = *llmExplain
+		if *llmExplain {
+			log.Printf("   explain : on (help for rule-based corrections)\n")
+		}
+		kospell.LLMVerify = *llmVerify
+		if *llmVerify {
+			log.Printf("   verify  : on (false positives go to suppressed)\n")
+		}
+	}
+
+	if *dictStore != "" {
+		store, err := kospell.OpenDictStore(*dictStore)
+		if err != nil {
+			log.Fatalf("dict store: %v", err)
+		}
+		kospell.Dicts = store
+		go store.Watch(context.Background(), kospell.DictStorePollInterval)
+		log.Printf("   dicts   : store %s (%d named)\n", *dictStore, len(store.Names()))
+	}
+
+	if *feedbackFile != "" {
+		kospell.DefaultFeedback = kospell.OpenFeedbackLog(*feedbackFile)
+		log.Printf("   feedback: %s\n", *feedbackFile)
+	}
+
 	http.HandleFunc("/v1/check-spell", kospell.CheckSpellHandler)
+	http.HandleFunc("/v1/check-spell/stream", kospell.CheckSpellStreamHandler)
+	http.HandleFunc("/v1/feedback", kospell.FeedbackHandler)
+	http.HandleFunc("/v1/feedback/report", kospell.FeedbackReportHandler)
+	http.HandleFunc("/v1/feedback/promote", kospell.FeedbackPromoteHandler)
+	http.HandleFunc("/v1/dicts", kospell.DictsHandler)
+	http.HandleFunc("/v1/dicts/{name}", kospell.DictHandler)
+	http.HandleFunc("/v1/dicts/{name}/words", kospell.DictWordsHandler)
+	http.HandleFunc("/v1/usage", kospell.UsageHandler)
 	http.HandleFunc("/health", kospell.HealthHandler)
 	http.HandleFunc("/openapi.json", kospell.OpenAPIHandler)
 	http.HandleFunc("/", kospell.DocsHandler)
@@ -72,14 +246,40 @@ func main() {
 	addr := fmt.Sprintf(":%s", *port)
 	log.Printf("🚀 kospell server listening on http://localhost:%s\n", *port)
 	log.Printf("   POST http://localhost:%s/v1/check-spell\n", *port)
+	log.Printf("   GET  http://localhost:%s/v1/dicts\n", *port)
 	log.Printf("   GET  http://localhost:%s/health\n", *port)
 	log.Printf("   GET  http://localhost:%s/       (Redoc UI)\n", *port)
 	log.Fatal(http.ListenAndServe(addr, nil))
 }
 
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func envOr(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
 	}
 	return fallback
 }
+
+func envBoolOr(key string, fallback bool) bool {
+	if v, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func envIntOr(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,264 @@
+package chunk
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sentences splits s into Korean sentences. Every sentence keeps the
+// whitespace that follows it, so strings.Join(Sentences(s), "") == s.
+//
+// A sentence ends after terminal punctuation (다. 요. 까? 죠! 。) and any
+// closing quotes or brackets, when followed by whitespace or the end of s.
+// It does not end:
+//   - inside quotes ("그는 "좋아요. 정말요." 라고 했다."),
+//   - before a quotative particle after a closing quote (…" 라고 / 하며),
+//   - after a list marker ("1.", "가.", "a)") or an abbreviation ("e.g."),
+//   - at an ellipsis ("그래서... 나는") unless it follows a final ending.
+//
+// Blank lines always end a sentence, and so does any line break next to a
+// list item. Quotes and brackets left open at the end of a line are closed
+// there.
+func Sentences(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var out []string
+	start := 0
+	depth := 0        // open curly quotes / brackets that quote speech
+	straight := false // inside a "…" pair
+	lineStart := 0
+
+	cut := func(end int) {
+		end = skipSpace(s, end)
+		if end > start {
+			out = append(out, s[start:end])
+			start = end
+		}
+	}
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case r == '\n':
+			// An unmatched quote or bracket must not run past its line; a
+			// line it kept from ending at its final punctuation ends here.
+			unbalanced := depth > 0 || straight
+			depth, straight = 0, false
+			next := i + size
+			if start < i && strings.TrimSpace(s[start:i]) != "" &&
+				(blankLineAt(s, next) || isListItem(s[lineStart:i]) || isListItem(s[next:]) ||
+					unbalanced && endsWithTerminal(s[lineStart:i])) {
+				cut(next)
+			}
+			lineStart = next
+			i = next
+			continue
+
+		case r == '"':
+			straight = !straight
+
+		case isOpenQuote(r):
+			depth++
+
+		case isCloseQuote(r):
+			if depth > 0 {
+				depth--
+			}
+
+		case isTerminal(r):
+			j, closedQuote := i, false
+			for j < len(s) {
+				r2, n := utf8.DecodeRuneInString(s[j:])
+				if !isTerminal(r2) {
+					break
+				}
+				j += n
+			}
+			run := s[i:j]
+			for j < len(s) {
+				r2, n := utf8.DecodeRuneInString(s[j:])
+				switch {
+				case r2 == '"' && straight:
+					straight, closedQuote = false, true
+				case isCloseQuote(r2):
+					if depth > 0 {
+						depth--
+					}
+					closedQuote = closedQuote || isQuote(r2)
+				default:
+					n = 0
+				}
+				if n == 0 {
+					break
+				}
+				j += n
+			}
+
+			if depth == 0 && !straight && endsSentence(s, lineStart, i, j, run, closedQuote) {
+				cut(j)
+			}
+			i = j
+			continue
+		}
+		i += size
+	}
+	if start < len(s) {
+		out = append(out, s[start:])
+	}
+	return out
+}
+
+// endsSentence decides whether the punctuation run s[i:j] (closers included)
+// ends a sentence.
+func endsSentence(s string, lineStart, i, j int, run string, closedQuote bool) bool {
+	if j < len(s) && !isBoundarySpace(s[j]) {
+		return false // "3.5", "a.b", "다.다음"
+	}
+	prev, _ := utf8.DecodeLastRuneInString(s[:i])
+
+	switch {
+	case run == ".":
+		word := lastWord(s[lineStart:i])
+		if isListItem(s[lineStart:j] + " ") {
+			return false
+		}
+		if isASCIIWord(word) && (utf8.RuneCountInString(word) == 1 || abbreviations[strings.ToLower(word)]) {
+			return false
+		}
+	case isEllipsis(run):
+		if !strings.ContainsRune(finalEndings, prev) {
+			return false
+		}
+	}
+
+	if closedQuote && followsQuotative(s[j:]) {
+		return false
+	}
+	return true
+}
+
+// endsWithTerminal reports whether line ends with terminal punctuation,
+// possibly followed by closing quotes or brackets.
+func endsWithTerminal(line string) bool {
+	line = strings.TrimRightFunc(line, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || isCloseQuote(r)
+	})
+	r, _ := utf8.DecodeLastRuneInString(line)
+	return isTerminal(r)
+}
+
+// finalEndings are syllables that commonly close a Korean sentence.
+const finalEndings = "다요까죠네지군나래자라니오어아야게세"
+
+var quotatives = []string{"라고", "라는", "라며", "라면서", "이라고", "하고", "하며", "하면서", "하는", "고 ", "며 "}
+
+// followsQuotative reports whether rest (after a closing quote) continues
+// the sentence with a quotative particle: …" 라고 말했다.
+func followsQuotative(rest string) bool {
+	rest = strings.TrimLeft(rest, " \t")
+	for _, q := range quotatives {
+		if strings.HasPrefix(rest, q) {
+			return true
+		}
+	}
+	return false
+}
+
+var abbreviations = map[string]bool{
+	"e.g": true, "i.e": true, "etc": true, "vs": true, "mr": true, "mrs": true, "ms": true,
+	"dr": true, "prof": true, "st": true, "inc": true, "co": true, "ltd": true, "no": true,
+	"jr": true, "sr": true, "fig": true, "p": true, "pp": true, "vol": true,
+}
+
+var reListItem = regexp.MustCompile(`^[ \t]*(?:\d{1,3}[.)]|\(\d{1,3}\)|[가-하][.)]|[A-Za-z][.)]|[-*•·▪]|[①-⑳])[ \t]`)
+
+// isListItem reports whether line starts with a list marker.
+func isListItem(line string) bool {
+	return reListItem.MatchString(line)
+}
+
+// blankLineAt reports whether the line starting at i is empty or blank.
+func blankLineAt(s string, i int) bool {
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case ' ', '\t', '\r':
+		case '\n':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func lastWord(s string) string {
+	if k := strings.LastIndexFunc(s, unicode.IsSpace); k >= 0 {
+		return s[k+1:]
+	}
+	return s
+}
+
+func isASCIIWord(w string) bool {
+	if w == "" {
+		return false
+	}
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func isTerminal(r rune) bool {
+	switch r {
+	case '.', '?', '!', '…', '。', '？', '！', '⋯':
+		return true
+	}
+	return false
+}
+
+func isEllipsis(run string) bool {
+	return strings.Trim(run, ".…⋯") == "" && run != "."
+}
+
+func isOpenQuote(r rune) bool {
+	switch r {
+	case '“', '‘', '「', '『', '(', '[', '《', '〈', '（':
+		return true
+	}
+	return false
+}
+
+func isCloseQuote(r rune) bool {
+	switch r {
+	case '”', '’', '」', '』', ')', ']', '》', '〉', '）':
+		return true
+	}
+	return false
+}
+
+func isQuote(r rune) bool {
+	switch r {
+	case '"', '”', '’', '」', '』':
+		return true
+	}
+	return false
+}
+
+func isBoundarySpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+func skipSpace(s string, i int) int {
+	for i < len(s) && isBoundarySpace(s[i]) {
+		i++
+	}
+	return i
+}
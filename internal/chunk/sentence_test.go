@@ -0,0 +1,90 @@
+package chunk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSentences(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"endings", "밥을 먹었다. 맛있어요. 또 올까? 좋죠!", []string{"밥을 먹었다. ", "맛있어요. ", "또 올까? ", "좋죠!"}},
+		{"decimal and no space", "버전은 1.2.3이다.다음 문장", []string{"버전은 1.2.3이다.다음 문장"}},
+		{"quoted speech", `그는 "좋아요. 정말요." 라고 말했다. 끝.`, []string{`그는 "좋아요. 정말요." 라고 말했다. `, "끝."}},
+		{"curly quote ends sentence", "“가자.” 그가 말했다.", []string{"“가자.” ", "그가 말했다."}},
+		{"ellipsis mid sentence", "그래서... 나는 갔다. 좋았다…", []string{"그래서... 나는 갔다. ", "좋았다…"}},
+		{"ellipsis after ending", "글쎄요... 모르겠어요.", []string{"글쎄요... ", "모르겠어요."}},
+		{"numbered list", "준비물:\n1. 연필을 챙긴다\n2. 지우개도 챙긴다\n끝이다.", []string{"준비물:\n", "1. 연필을 챙긴다\n", "2. 지우개도 챙긴다\n", "끝이다."}},
+		{"korean list marker", "가. 첫째 항목\n나. 둘째 항목", []string{"가. 첫째 항목\n", "나. 둘째 항목"}},
+		{"abbreviation", "예를 들어 e.g. 사과 등이 있다. 끝", []string{"예를 들어 e.g. 사과 등이 있다. ", "끝"}},
+		{"blank line", "제목\n\n본문이다", []string{"제목\n\n", "본문이다"}},
+		{"unbalanced quote", "그는 \"안녕. 잘 가.\n다음 줄이다. 끝이다.", []string{"그는 \"안녕. 잘 가.\n", "다음 줄이다. ", "끝이다."}},
+		{"unbalanced bracket", "(참고: 아래 표\n둘째 줄이다. 셋째 줄이다.", []string{"(참고: 아래 표\n둘째 줄이다. ", "셋째 줄이다."}},
+		{"multiple spaces kept", "하나다.   둘이다.", []string{"하나다.   ", "둘이다."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sentences(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Sentences(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+			if strings.Join(got, "") != tt.in {
+				t.Fatalf("sentences do not rejoin to the input")
+			}
+		})
+	}
+}
+
+func TestSplitWords_KeepsSentencesWhole(t *testing.T) {
+	sentence := strings.TrimSpace(strings.Repeat("단어 ", 99)) + "다. " // 100 어절
+	text := strings.TrimSpace(strings.Repeat(sentence, 5))
+
+	parts := SplitWords(text, 300)
+	if len(parts) != 2 {
+		t.Fatalf("len(parts) = %d, want 2", len(parts))
+	}
+	for i, p := range parts {
+		if !strings.HasSuffix(p, "다.") {
+			t.Fatalf("parts[%d] ends mid-sentence: ...%q", i, p[len(p)-20:])
+		}
+		if n := len(strings.Fields(p)); n > 300 {
+			t.Fatalf("parts[%d] has %d 어절, want <= 300", i, n)
+		}
+	}
+	if strings.Join(parts, " ") != text {
+		t.Fatal("parts do not rejoin to the input")
+	}
+}
+
+func TestSplitWords_FallsBackForHugeSentence(t *testing.T) {
+	text := strings.Repeat("x ", 700) + "끝."
+	parts := SplitWords(text, 300)
+	if len(parts) != 3 {
+		t.Fatalf("len(parts) = %d, want 3", len(parts))
+	}
+	if strings.Join(parts, " ") != text {
+		t.Fatal("parts do not rejoin to the input")
+	}
+}
+
+func TestSplitRunes_KeepsSentencesWhole(t *testing.T) {
+	sentence := strings.Repeat("가나다라 ", 15) + "끝났다. " // 86 runes
+	text := strings.Repeat(sentence, 6)
+
+	parts := SplitRunes(text, 300)
+	if strings.Join(parts, "") != text {
+		t.Fatal("parts do not rejoin to the input")
+	}
+	for i, p := range parts {
+		if n := len([]rune(p)); n > 300 {
+			t.Fatalf("parts[%d] has %d runes, want <= 300", i, n)
+		}
+		if !strings.HasSuffix(p, "끝났다. ") {
+			t.Fatalf("parts[%d] ends mid-sentence", i)
+		}
+	}
+}
@@ -1,27 +1,131 @@
 package chunk
 
-// Split300 slices the string into ≤300-어절 chunks
-// without decoding UTF-8 runes.  ZERO copies, 1 slice alloc.
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Split300 slices the string into ≤300-어절 chunks of whole sentences.
+// See SplitWords.
 func Split300(s string) []string {
-	const max = 300
-
-	// Capacity hint: assume “avg 5-bytes‐word + 1 space”.
-	// For 5 000 어절 ≈ 30 000 B ⇒ hint ≈ 3.  Good enough.
-	hint := len(s)/(max*6) + 1
-	res := make([]string, 0, hint)
-
-	start, words := 0, 0
-	for i := 0; i < len(s); i++ {
-		b := s[i]
-		if b == ' ' || b == '\n' {
-			words++
-			if words == max {
-				res = append(res, s[start:i])
-				start, words = i+1, 0
-			}
+	return SplitWords(s, 300)
+}
+
+// SplitWords packs whole sentences into chunks of at most max 어절.
+// Whitespace between chunks belongs to neither, so for text whose words are
+// separated by single spaces strings.Join(chunks, " ") rebuilds s; use
+// Strategy.Split and Rejoin to keep arbitrary whitespace. A sentence longer
+// than max is cut between words.
+func SplitWords(s string, max int) []string {
+	pieces := wordPieces(s, max)
+	out := make([]string, len(pieces))
+	for i, p := range pieces {
+		out[i] = p.Text
+	}
+	return out
+}
+
+// wordPieces implements SplitWords, recording where each chunk starts.
+func wordPieces(s string, max int) []Piece {
+	var out []Piece
+	start, words, pos := 0, 0, 0
+	for _, sent := range Sentences(s) {
+		n := len(strings.Fields(sent))
+		if words > 0 && words+n > max {
+			out = appendWordPieces(out, s, start, pos, max)
+			start, words = pos, 0
+		}
+		words += n
+		pos += len(sent)
+	}
+	out = appendWordPieces(out, s, start, len(s), max)
+	if len(out) == 0 {
+		out = append(out, Piece{Text: s, Start: 0})
+	}
+	return out
+}
+
+// appendWordPieces cuts s[start:end] after every max-th word without
+// decoding UTF-8 runes. Whitespace around the pieces is left out. ZERO copies.
+func appendWordPieces(out []Piece, s string, start, end, max int) []Piece {
+	first, words := -1, 0
+	for i := start; i < end; {
+		if isBoundarySpace(s[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < end && !isBoundarySpace(s[j]) {
+			j++
+		}
+		if first < 0 {
+			first = i
+		}
+		words++
+		if words == max || skipSpace(s[:end], j) == end {
+			out = append(out, Piece{Text: s[first:j], Start: first})
+			first, words = -1, 0
+		}
+		i = j
+	}
+	return out
+}
+
+// SplitRunes packs whole sentences into chunks of at most max runes.
+// The chunks partition s: strings.Join(chunks, "") == s. A sentence longer
+// than max is cut at the last whitespace in the second half of the window,
+// or hard at max runes when there is none.
+func SplitRunes(s string, max int) []string {
+	if s == "" {
+		return []string{""}
+	}
+	if utf8.RuneCountInString(s) <= max {
+		return []string{s}
+	}
+
+	var res []string
+	var cur strings.Builder
+	size := 0
+	for _, sent := range Sentences(s) {
+		n := utf8.RuneCountInString(sent)
+		if size > 0 && size+n > max {
+			res = append(res, cur.String())
+			cur.Reset()
+			size = 0
 		}
+		if n > max {
+			res = append(res, splitRunes(sent, max)...)
+			continue
+		}
+		cur.WriteString(sent)
+		size += n
+	}
+	if size > 0 {
+		res = append(res, cur.String())
 	}
-	// trailing slice (never empty because start ≤ len(s))
-	res = append(res, s[start:])
 	return res
 }
+
+func splitRunes(s string, max int) []string {
+	runes := []rune(s)
+	parts := make([]string, 0, (len(runes)+max-1)/max)
+	for start := 0; start < len(runes); {
+		end := start + max
+		if end >= len(runes) {
+			parts = append(parts, string(runes[start:]))
+			break
+		}
+
+		cut := end
+		for i := end; i > start+max/2; i-- {
+			if runes[i-1] == ' ' || runes[i-1] == '\n' || runes[i-1] == '\t' {
+				cut = i
+				break
+			}
+		}
+
+		parts = append(parts, string(runes[start:cut]))
+		start = cut
+	}
+	return parts
+}
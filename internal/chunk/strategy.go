@@ -0,0 +1,246 @@
+package chunk
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limit is the unit a backend measures its input limit in.
+type Limit int
+
+const (
+	Unlimited Limit = iota // the whole text is sent at once (hunspell)
+	Words                  // 어절 (nara)
+	Runes                  // characters (Naver)
+	Tokens                 // estimated model tokens (LLMs)
+)
+
+func (l Limit) String() string {
+	switch l {
+	case Words:
+		return "words"
+	case Runes:
+		return "runes"
+	case Tokens:
+		return "tokens"
+	default:
+		return "unlimited"
+	}
+}
+
+// Strategy describes how a backend wants its input cut: chunks of whole
+// sentences of at most Size units. Overlap (Tokens only) repeats up to that
+// many tokens of the preceding sentences at the start of each chunk so the
+// model keeps context across the seam.
+type Strategy struct {
+	Limit   Limit
+	Size    int
+	Overlap int
+}
+
+// Piece is one chunk of a text.
+type Piece struct {
+	Text    string // sent upstream, overlap context included
+	Start   int    // byte offset of Text in the source
+	Context int    // leading bytes of Text repeated from the previous piece
+}
+
+// Own returns the part of the piece that is not overlap context.
+func (p Piece) Own() string { return p.Text[p.Context:] }
+
+// Split cuts text according to the strategy. Cuts always fall on rune
+// boundaries.
+//
+//   - Words: pieces leave out the whitespace between them (SplitWords).
+//   - Runes: pieces partition text (SplitRunes).
+//   - Tokens: the Own parts partition text (SplitTokens).
+//   - Unlimited, or Size <= 0: one piece.
+func (s Strategy) Split(text string) []Piece {
+	if s.Size <= 0 {
+		return []Piece{{Text: text}}
+	}
+	switch s.Limit {
+	case Words:
+		return wordPieces(text, s.Size)
+	case Runes:
+		return piecesOf(SplitRunes(text, s.Size))
+	case Tokens:
+		return SplitTokens(text, s.Size, s.Overlap)
+	default:
+		return []Piece{{Text: text}}
+	}
+}
+
+// piecesOf locates the parts of a partition.
+func piecesOf(parts []string) []Piece {
+	out := make([]Piece, len(parts))
+	pos := 0
+	for i, p := range parts {
+		out[i] = Piece{Text: p, Start: pos}
+		pos += len(p)
+	}
+	return out
+}
+
+// EstimateTokens approximates how many tokens a BPE chat-model tokenizer
+// spends on s: about one per Hangul syllable or other non-ASCII rune and one
+// per four ASCII bytes. It errs on the high side for Korean.
+func EstimateTokens(s string) int {
+	n, ascii := 0, 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+			continue
+		}
+		n++
+	}
+	return n + (ascii+3)/4
+}
+
+type tokenUnit struct {
+	start, end int
+	tokens     int
+}
+
+// SplitTokens packs whole sentences into pieces of at most max estimated
+// tokens, context included. Each piece after the first starts with up to
+// overlap tokens of the sentences before it (clamped to max/2), fewer when
+// its first sentence leaves no room. Sentences over the limit are cut
+// between words, and words over it between runes.
+func SplitTokens(text string, max, overlap int) []Piece {
+	if EstimateTokens(text) <= max {
+		return []Piece{{Text: text}}
+	}
+	if overlap > max/2 {
+		overlap = max / 2
+	}
+
+	var units []tokenUnit
+	pos := 0
+	for _, sent := range Sentences(text) {
+		units = appendTokenUnits(units, text, pos, pos+len(sent), max)
+		pos += len(sent)
+	}
+
+	var pieces []Piece
+	for i := 0; i < len(units); {
+		c, ctxTokens := i, 0
+		if len(pieces) > 0 {
+			for c > 0 && ctxTokens+units[c-1].tokens <= overlap {
+				c--
+				ctxTokens += units[c].tokens
+			}
+			// Context gives way to the piece's own first unit.
+			for c < i && ctxTokens+units[i].tokens > max {
+				ctxTokens -= units[c].tokens
+				c++
+			}
+		}
+		j, tokens := i, ctxTokens
+		for j < len(units) && (j == i || tokens+units[j].tokens <= max) {
+			tokens += units[j].tokens
+			j++
+		}
+		start := units[c].start
+		pieces = append(pieces, Piece{
+			Text:    text[start:units[j-1].end],
+			Start:   start,
+			Context: units[i].start - start,
+		})
+		i = j
+	}
+	return pieces
+}
+
+// appendTokenUnits adds text[start:end] as one unit, or as word (then rune)
+// units when it is over max tokens.
+func appendTokenUnits(units []tokenUnit, text string, start, end, max int) []tokenUnit {
+	if t := EstimateTokens(text[start:end]); t <= max {
+		return append(units, tokenUnit{start, end, t})
+	}
+
+	// words, each keeping its trailing whitespace
+	for i := start; i < end; {
+		j := i
+		for j < end {
+			r, n := utf8.DecodeRuneInString(text[j:])
+			if unicode.IsSpace(r) {
+				break
+			}
+			j += n
+		}
+		for j < end {
+			r, n := utf8.DecodeRuneInString(text[j:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			j += n
+		}
+		if t := EstimateTokens(text[i:j]); t <= max || j-i <= utf8.UTFMax {
+			units = append(units, tokenUnit{i, j, t})
+		} else {
+			units = appendRuneUnits(units, text, i, j, max)
+		}
+		i = j
+	}
+	return units
+}
+
+// appendRuneUnits hard-cuts text[start:end] into runs of at most max tokens.
+func appendRuneUnits(units []tokenUnit, text string, start, end, max int) []tokenUnit {
+	i, wide, ascii := start, 0, 0
+	for j := start; j < end; {
+		r, n := utf8.DecodeRuneInString(text[j:])
+		w, a := wide, ascii
+		if r < utf8.RuneSelf {
+			a++
+		} else {
+			w++
+		}
+		if j > i && w+(a+3)/4 > max {
+			units = append(units, tokenUnit{i, j, wide + (ascii+3)/4})
+			i, wide, ascii = j, 0, 0
+			continue
+		}
+		wide, ascii = w, a
+		j += n
+	}
+	if i < end {
+		units = append(units, tokenUnit{i, end, wide + (ascii+3)/4})
+	}
+	return units
+}
+
+// Rejoin puts parts (one per piece, replacing its Own text) back into text,
+// keeping everything between and around the pieces byte for byte: dropped
+// separators, blank lines and leading or trailing whitespace.
+func Rejoin(text string, pieces []Piece, parts []string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	pos := 0
+	for i, p := range pieces {
+		own := p.Start + p.Context
+		if own >= pos {
+			b.WriteString(text[pos:own])
+		}
+		b.WriteString(parts[i])
+		pos = p.Start + len(p.Text)
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
+
+// RuneStarts returns the rune offset in text at which each piece's Own text
+// begins. Pieces must be in order.
+func RuneStarts(text string, pieces []Piece) []int {
+	out := make([]int, len(pieces))
+	pos, n := 0, 0
+	for i, p := range pieces {
+		own := p.Start + p.Context
+		n += utf8.RuneCountInString(text[pos:own])
+		pos = own
+		out[i] = n
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package chunk
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"한국어", 3},
+		{"한국어 text", 5},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.in); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitTokens_Overlap(t *testing.T) {
+	text := strings.Repeat("오늘은 날씨가 좋다. ", 40) + "끝."
+
+	pieces := SplitTokens(text, 50, 12)
+	if len(pieces) < 2 {
+		t.Fatalf("got %d pieces, want several", len(pieces))
+	}
+	var own strings.Builder
+	for i, p := range pieces {
+		if got := EstimateTokens(p.Text); got > 50 {
+			t.Errorf("piece %d has %d tokens, want ≤ 50", i, got)
+		}
+		if text[p.Start:p.Start+len(p.Text)] != p.Text {
+			t.Errorf("piece %d Start does not point at its text", i)
+		}
+		if i == 0 && p.Context != 0 {
+			t.Errorf("first piece has context %q", p.Text[:p.Context])
+		}
+		if i > 0 && p.Context == 0 {
+			t.Errorf("piece %d has no overlap context", i)
+		}
+		if EstimateTokens(p.Text[:p.Context]) > 12 {
+			t.Errorf("piece %d context %q exceeds the overlap", i, p.Text[:p.Context])
+		}
+		own.WriteString(p.Own())
+	}
+	if own.String() != text {
+		t.Fatal("own parts do not partition the text")
+	}
+}
+
+func TestSplitTokens_ContextNeverOverflows(t *testing.T) {
+	long := strings.Repeat("가나다라 ", 9) + "끝. " // 40 tokens
+	text := strings.Repeat("짧다. ", 10) + long + strings.Repeat("짧다. ", 10) + long
+
+	pieces := SplitTokens(text, 50, 20)
+	var own strings.Builder
+	for i, p := range pieces {
+		if got := EstimateTokens(p.Text); got > 50 {
+			t.Errorf("piece %d has %d tokens, want ≤ 50: %q", i, got, p.Text)
+		}
+		own.WriteString(p.Own())
+	}
+	if own.String() != text {
+		t.Fatal("own parts do not partition the text")
+	}
+}
+
+func TestSplitTokens_NoOverlap(t *testing.T) {
+	text := strings.Repeat("문장이다. ", 30)
+	for _, p := range SplitTokens(text, 20, 0) {
+		if p.Context != 0 {
+			t.Fatalf("unexpected context %q", p.Text[:p.Context])
+		}
+	}
+}
+
+func TestSplitTokens_LongWordCutsOnRunes(t *testing.T) {
+	text := strings.Repeat("가", 25) + " " + strings.Repeat("x", 30)
+
+	pieces := SplitTokens(text, 10, 0)
+	var own strings.Builder
+	for i, p := range pieces {
+		if !utf8.ValidString(p.Text) {
+			t.Fatalf("piece %d cut inside a rune: %q", i, p.Text)
+		}
+		if got := EstimateTokens(p.Text); got > 10 {
+			t.Errorf("piece %d has %d tokens, want ≤ 10", i, got)
+		}
+		own.WriteString(p.Own())
+	}
+	if own.String() != text {
+		t.Fatal("pieces do not partition the text")
+	}
+}
+
+func TestStrategy_Split(t *testing.T) {
+	text := "하나 둘 셋. 넷 다섯 여섯. 일곱 여덟."
+
+	words := Strategy{Limit: Words, Size: 3}.Split(text)
+	if len(words) != 3 {
+		t.Fatalf("Words: got %d pieces, want 3", len(words))
+	}
+	for i, p := range words {
+		if text[p.Start:p.Start+len(p.Text)] != p.Text {
+			t.Errorf("Words piece %d Start does not point at %q", i, p.Text)
+		}
+	}
+
+	runes := Strategy{Limit: Runes, Size: 8}.Split(text)
+	var b strings.Builder
+	for i, p := range runes {
+		if text[p.Start:p.Start+len(p.Text)] != p.Text {
+			t.Errorf("Runes piece %d Start does not point at %q", i, p.Text)
+		}
+		b.WriteString(p.Text)
+	}
+	if b.String() != text {
+		t.Fatal("Runes pieces do not partition the text")
+	}
+
+	for _, s := range []Strategy{{Limit: Unlimited, Size: 1}, {Limit: Words}} {
+		if got := s.Split(text); len(got) != 1 || got[0].Text != text {
+			t.Errorf("%v/%d: got %d pieces, want the whole text", s.Limit, s.Size, len(got))
+		}
+	}
+}
+
+func TestRejoin_RoundTrip(t *testing.T) {
+	text := "첫 문단의 첫 문장이다. 둘째 문장이다.\n\n둘째 문단이다.\n  들여쓴 줄이다.\n\n\n- 목록 하나\n- 목록 둘\n"
+	for _, s := range []Strategy{
+		{Limit: Words, Size: 3},
+		{Limit: Runes, Size: 10},
+		{Limit: Tokens, Size: 12, Overlap: 4},
+		{Limit: Unlimited},
+	} {
+		pieces := s.Split(text)
+		parts := make([]string, len(pieces))
+		for i, p := range pieces {
+			parts[i] = p.Own()
+		}
+		if got := Rejoin(text, pieces, parts); got != text {
+			t.Errorf("%v: Rejoin = %q, want the input back", s.Limit, got)
+		}
+	}
+}
+
+func TestRejoin_KeepsSeparators(t *testing.T) {
+	text := "가나 다라.\n\n마바 사아."
+	pieces := Strategy{Limit: Words, Size: 2}.Split(text)
+	if len(pieces) != 2 {
+		t.Fatalf("got %d pieces, want 2", len(pieces))
+	}
+	got := Rejoin(text, pieces, []string{"A", "B"})
+	if want := "A\n\nB"; got != want {
+		t.Fatalf("Rejoin = %q, want %q", got, want)
+	}
+}
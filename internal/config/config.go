@@ -0,0 +1,222 @@
+// Package config loads the project configuration file (.kospell.yaml) shared
+// by kospell-cli and kospell-server.
+//
+// Settings are resolved in this order, highest first:
+//
+//  1. command-line flags
+//  2. environment variables (server: MODE, DICT_DIR, LLM_MODEL, …)
+//  3. the configuration file
+//  4. built-in defaults
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the names looked up in every directory, in order.
+var FileNames = []string{".kospell.yaml", ".kospell.yml"}
+
+// Config mirrors .kospell.yaml.
+type Config struct {
+	Backend    string   `yaml:"backend"`     // nara | hunspell | hanspell | openai
+	Dicts      []string `yaml:"dicts"`       // user dictionary JSON files
+	ErrorTypes []string `yaml:"error_types"` // reported error types
+	Mask       []string `yaml:"mask"`        // masked span categories (url, email, …, all, none)
+	Feedback   string   `yaml:"feedback"`    // feedback log (JSON lines)
+	Ignore     []string `yaml:"ignore"`      // path globs skipped by directory scans
+	Format     string   `yaml:"format"`      // json | sarif | checkstyle | junit
+	Timeout    string   `yaml:"timeout"`     // Go duration, e.g. "30s"
+
+	Hunspell HunspellConfig `yaml:"hunspell"`
+	OpenAI   OpenAIConfig   `yaml:"openai"`
+
+	// Dir is the directory holding the file. Relative paths in Dicts,
+	// Feedback, Hunspell.DictDir and OpenAI.Prompts are resolved against it
+	// by Load.
+	Dir string `yaml:"-"`
+}
+
+// HunspellConfig holds hunspell backend options.
+type HunspellConfig struct {
+	DictDir string `yaml:"dict_dir"`
+	Lang    string `yaml:"lang"`
+}
+
+// OpenAIConfig holds LLM backend options. The API key is deliberately not
+// part of the file; it comes from -llm-key or OPENAI_API_KEY.
+type OpenAIConfig struct {
+	Provider     string `yaml:"provider"` // openai | responses | anthropic | ollama
+	Model        string `yaml:"model"`
+	BaseURL      string `yaml:"base_url"`
+	ChunkTokens  int    `yaml:"chunk_tokens"`  // estimated tokens per prompt; 0 keeps the default
+	ChunkOverlap int    `yaml:"chunk_overlap"` // context tokens repeated per prompt; 0 keeps the default
+	Prompts      string `yaml:"prompts"`       // directory of <name>.tmpl prompt templates
+	Prompt       string `yaml:"prompt"`        // default prompt name
+
+	Prices         map[string]Price `yaml:"prices"`          // per-model prices, USD per million tokens
+	DailyTokens    int              `yaml:"daily_tokens"`    // per-day token budget; 0 means none
+	BudgetFallback string           `yaml:"budget_fallback"` // backend used once the budget is spent; "" rejects
+
+	Explain bool `yaml:"explain"` // LLM-written help for rule-based backends' corrections
+	Verify  bool `yaml:"verify"`  // LLM false-positive check of rule-based backends' corrections
+}
+
+// Price is a model's price in USD per million tokens.
+type Price struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// Find walks up from dir looking for a configuration file and returns its
+// path, or "" if none exists up to the filesystem root.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range FileNames {
+			p := filepath.Join(dir, name)
+			info, err := os.Stat(p)
+			if err == nil && !info.IsDir() {
+				return p, nil
+			}
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load parses the file at p and resolves its relative paths.
+func Load(p string) (*Config, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", p, err)
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return nil, err
+	}
+	c.Dir = filepath.Dir(abs)
+	for i, d := range c.Dicts {
+		c.Dicts[i] = c.resolve(d)
+	}
+	if c.Hunspell.DictDir != "" {
+		c.Hunspell.DictDir = c.resolve(c.Hunspell.DictDir)
+	}
+	if c.Feedback != "" {
+		c.Feedback = c.resolve(c.Feedback)
+	}
+	if c.OpenAI.Prompts != "" {
+		c.OpenAI.Prompts = c.resolve(c.OpenAI.Prompts)
+	}
+	return &c, nil
+}
+
+// Discover loads the file named by explicit, or the first one found by
+// walking up from the working directory. It returns an empty Config when
+// no file exists.
+func Discover(explicit string) (*Config, error) {
+	p := explicit
+	if p == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		if p, err = Find(wd); err != nil {
+			return nil, err
+		}
+	}
+	if p == "" {
+		return &Config{}, nil
+	}
+	return Load(p)
+}
+
+func (c *Config) resolve(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(c.Dir, p)
+}
+
+// Ignored reports whether p matches one of the ignore globs. Patterns
+// without a slash match the base name (e.g. "*.min.md"); others match the
+// slash path relative to the config directory, where a trailing "/**"
+// matches everything below that directory.
+func (c *Config) Ignored(p string) bool {
+	if c == nil || len(c.Ignore) == 0 {
+		return false
+	}
+
+	rel := p
+	if c.Dir != "" {
+		if abs, err := filepath.Abs(p); err == nil {
+			if r, err := filepath.Rel(c.Dir, abs); err == nil {
+				rel = r
+			}
+		}
+	}
+	rel = filepath.ToSlash(rel)
+	base := path.Base(rel)
+
+	for _, pat := range c.Ignore {
+		pat = strings.TrimPrefix(filepath.ToSlash(pat), "./")
+		if !strings.Contains(pat, "/") {
+			if ok, _ := path.Match(pat, base); ok {
+				return true
+			}
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(pat, "/**"); ok {
+			if rel == prefix || strings.HasPrefix(rel, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pat, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFlags returns the names of the flags given explicitly on the command line.
+func SetFlags(fs *flag.FlagSet) map[string]bool {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// Apply fills *dst with the file value v unless the flag name was given on
+// the command line or the environment variable env is set (flags read their
+// env defaults themselves). Empty file values leave *dst untouched.
+func Apply(set map[string]bool, name, env string, dst *string, v string) {
+	if v == "" || set[name] {
+		return
+	}
+	if env != "" && os.Getenv(env) != "" {
+		return
+	}
+	*dst = v
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindAndLoad_WalksUp(t *testing.T) {
+	root := t.TempDir()
+	data := "backend: hanspell\ndicts: [dict/brand.json]\nerror_types: [spelling]\nignore: [\"vendor/**\", \"*.min.md\"]\nhunspell:\n  lang: ko\n"
+	if err := os.WriteFile(filepath.Join(root, ".kospell.yaml"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Find(sub)
+	if err != nil || p != filepath.Join(root, ".kospell.yaml") {
+		t.Fatalf("Find = %q, %v", p, err)
+	}
+
+	c, err := Load(p)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Backend != "hanspell" || c.Hunspell.Lang != "ko" {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+	if want := filepath.Join(root, "dict", "brand.json"); len(c.Dicts) != 1 || c.Dicts[0] != want {
+		t.Fatalf("Dicts = %v, want [%s]", c.Dicts, want)
+	}
+
+	if !c.Ignored(filepath.Join(root, "vendor", "x", "a.md")) {
+		t.Fatal("vendor/** should be ignored")
+	}
+	if !c.Ignored(filepath.Join(root, "docs", "a.min.md")) {
+		t.Fatal("*.min.md should be ignored")
+	}
+	if c.Ignored(filepath.Join(root, "docs", "a.md")) {
+		t.Fatal("docs/a.md should not be ignored")
+	}
+}
+
+func TestApply_Precedence(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	mode := fs.String("mode", "nara", "")
+	lang := fs.String("lang", "ko", "")
+	model := fs.String("model", "m", "")
+	if err := fs.Parse([]string{"-mode", "hunspell"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KOSPELL_TEST_MODEL", "from-env")
+	set := SetFlags(fs)
+
+	Apply(set, "mode", "", mode, "openai")
+	Apply(set, "lang", "", lang, "en")
+	Apply(set, "model", "KOSPELL_TEST_MODEL", model, "from-file")
+
+	if *mode != "hunspell" {
+		t.Fatalf("flag should win: mode = %q", *mode)
+	}
+	if *lang != "en" {
+		t.Fatalf("file should fill unset flag: lang = %q", *lang)
+	}
+	if *model != "m" {
+		t.Fatalf("env should win over file: model = %q", *model)
+	}
+}
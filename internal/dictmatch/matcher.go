@@ -0,0 +1,249 @@
+// Package dictmatch finds user-dictionary words in text regardless of the
+// whitespace inside them ("목제 솜 틀기" matches "목제솜틀기").
+//
+// A Matcher is an Aho-Corasick automaton over the whitespace-compacted form
+// of every word. Text is compacted the same way while scanning, and matches
+// are mapped back to byte offsets in the original text, so one pass finds
+// every word no matter how large the dictionary is.
+package dictmatch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Word is a normalized dictionary entry.
+type Word struct {
+	Canonical string // inner whitespace collapsed to single spaces
+	Compact   string // all whitespace removed
+}
+
+// Match is one occurrence of Words()[Word] in the scanned text.
+// Start/End are byte offsets; the span may contain whitespace.
+type Match struct {
+	Start, End int
+	Word       int
+}
+
+// Matcher is immutable after New and safe for concurrent use.
+type Matcher struct {
+	words []Word
+	lens  []int // compact rune length per word
+	nodes []node
+}
+
+type node struct {
+	next map[rune]int32
+	fail int32
+	out  []int32 // word ids ending here, including via fail links
+}
+
+// New normalizes and deduplicates words and compiles them. Empty words are
+// dropped. Words() is ordered longest (compact rune count) first.
+func New(words []string) *Matcher {
+	m := &Matcher{nodes: []node{{}}}
+
+	seen := make(map[string]struct{}, len(words))
+	for _, raw := range words {
+		w := Normalize(raw)
+		if w.Compact == "" {
+			continue
+		}
+		if _, ok := seen[w.Canonical]; ok {
+			continue
+		}
+		seen[w.Canonical] = struct{}{}
+		m.words = append(m.words, w)
+	}
+	sort.Slice(m.words, func(i, j int) bool {
+		li := utf8.RuneCountInString(m.words[i].Compact)
+		lj := utf8.RuneCountInString(m.words[j].Compact)
+		if li == lj {
+			return m.words[i].Canonical < m.words[j].Canonical
+		}
+		return li > lj
+	})
+
+	m.lens = make([]int, len(m.words))
+	for id, w := range m.words {
+		m.lens[id] = utf8.RuneCountInString(w.Compact)
+		cur := int32(0)
+		for _, r := range w.Compact {
+			nx, ok := m.nodes[cur].next[r]
+			if !ok {
+				nx = int32(len(m.nodes))
+				m.nodes = append(m.nodes, node{})
+				if m.nodes[cur].next == nil {
+					m.nodes[cur].next = map[rune]int32{}
+				}
+				m.nodes[cur].next[r] = nx
+			}
+			cur = nx
+		}
+		m.nodes[cur].out = append(m.nodes[cur].out, int32(id))
+	}
+	m.link()
+	return m
+}
+
+// link computes failure links breadth-first and merges outputs.
+func (m *Matcher) link() {
+	queue := make([]int32, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		m.nodes[child].fail = 0
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].next {
+			f := m.nodes[cur].fail
+			for {
+				if nx, ok := m.nodes[f].next[r]; ok && nx != child {
+					m.nodes[child].fail = nx
+					break
+				}
+				if f == 0 {
+					m.nodes[child].fail = 0
+					break
+				}
+				f = m.nodes[f].fail
+			}
+			fo := m.nodes[m.nodes[child].fail].out
+			if len(fo) > 0 {
+				m.nodes[child].out = append(m.nodes[child].out, fo...)
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Normalize returns the canonical and compact forms of raw.
+func Normalize(raw string) Word {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return Word{}
+	}
+	return Word{
+		Canonical: strings.Join(fields, " "),
+		Compact:   strings.Join(fields, ""),
+	}
+}
+
+// Words returns the compiled words, longest first.
+func (m *Matcher) Words() []Word { return m.words }
+
+// Len returns the number of compiled words.
+func (m *Matcher) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.words)
+}
+
+// scan reports every (possibly overlapping) occurrence of every word in s.
+func (m *Matcher) scan(s string, emit func(Match)) {
+	if m.Len() == 0 || s == "" {
+		return
+	}
+
+	// starts[k]/ends[k] are the byte span of the k-th non-space rune.
+	starts := make([]int, 0, len(s)/2)
+	ends := make([]int, 0, len(s)/2)
+	state := int32(0)
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		starts = append(starts, i)
+		ends = append(ends, i+utf8.RuneLen(r))
+
+		for {
+			if nx, ok := m.nodes[state].next[r]; ok {
+				state = nx
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = m.nodes[state].fail
+		}
+		k := len(starts) - 1
+		for _, id := range m.nodes[state].out {
+			emit(Match{Start: starts[k-m.lens[id]+1], End: ends[k], Word: int(id)})
+		}
+	}
+}
+
+// Present returns the ids of the words that occur anywhere in s.
+func (m *Matcher) Present(s string) map[int]struct{} {
+	found := map[int]struct{}{}
+	m.scan(s, func(mt Match) { found[mt.Word] = struct{}{} })
+	return found
+}
+
+// FindAll returns non-overlapping matches in s, ordered by Start. Longer
+// words win over shorter ones, then earlier matches over later ones. When
+// only is non-nil, other words are ignored.
+func (m *Matcher) FindAll(s string, only map[int]struct{}) []Match {
+	var all []Match
+	m.scan(s, func(mt Match) {
+		if only != nil {
+			if _, ok := only[mt.Word]; !ok {
+				return
+			}
+		}
+		all = append(all, mt)
+	})
+	if len(all) == 0 {
+		return nil
+	}
+
+	// Word ids are already ordered longest first.
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Word != all[j].Word {
+			return all[i].Word < all[j].Word
+		}
+		return all[i].Start < all[j].Start
+	})
+
+	taken := make([]bool, len(s))
+	picked := all[:0]
+next:
+	for _, mt := range all {
+		for i := mt.Start; i < mt.End; i++ {
+			if taken[i] {
+				continue next
+			}
+		}
+		for i := mt.Start; i < mt.End; i++ {
+			taken[i] = true
+		}
+		picked = append(picked, mt)
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].Start < picked[j].Start })
+	return picked
+}
+
+// Canonicalize rewrites every match in s to its word's canonical spacing
+// and reports whether s changed. only restricts the words as in FindAll.
+func (m *Matcher) Canonicalize(s string, only map[int]struct{}) (string, bool) {
+	matches := m.FindAll(s, only)
+	if len(matches) == 0 {
+		return s, false
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	last := 0
+	for _, mt := range matches {
+		b.WriteString(s[last:mt.Start])
+		b.WriteString(m.words[mt.Word].Canonical)
+		last = mt.End
+	}
+	b.WriteString(s[last:])
+	out := b.String()
+	return out, out != s
+}
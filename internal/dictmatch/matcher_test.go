@@ -0,0 +1,37 @@
+package dictmatch
+
+import "testing"
+
+func TestCanonicalize_CollapsesInnerSpaces(t *testing.T) {
+	m := New([]string{"목제솜틀기", "우아한  형제들", "솜"})
+
+	got, changed := m.Canonicalize("나는 목제 솜 틀기 를, 회사명은 우 아한형제들 이야.", nil)
+	want := "나는 목제솜틀기 를, 회사명은 우아한 형제들 이야."
+	if !changed || got != want {
+		t.Fatalf("Canonicalize = %q (%v), want %q", got, changed, want)
+	}
+}
+
+func TestFindAll_LongestWinsAndOffsets(t *testing.T) {
+	m := New([]string{"형제", "우아한형제들"})
+	s := "a 우아한 형제들 b 형제"
+
+	got := m.FindAll(s, nil)
+	if len(got) != 2 {
+		t.Fatalf("len(matches) = %d, want 2: %+v", len(got), got)
+	}
+	if span := s[got[0].Start:got[0].End]; span != "우아한 형제들" {
+		t.Fatalf("first span = %q", span)
+	}
+	if span := s[got[1].Start:got[1].End]; span != "형제" || m.Words()[got[1].Word].Canonical != "형제" {
+		t.Fatalf("second span = %q", span)
+	}
+}
+
+func TestPresent_OverlappingWords(t *testing.T) {
+	m := New([]string{"abcd", "bc", "cde"})
+	ids := m.Present("x a b c d e")
+	if len(ids) != 3 {
+		t.Fatalf("Present = %v, want all 3 words", ids)
+	}
+}
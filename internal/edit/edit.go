@@ -0,0 +1,140 @@
+// Package edit applies span replacements to a text safely: spans are
+// validated against the text, offsets that drifted are repaired by looking
+// for the expected origin nearby, and overlapping spans are resolved with a
+// fixed policy instead of corrupting the output.
+package edit
+
+import (
+	"sort"
+	"strings"
+)
+
+// Reason says why an edit was not applied.
+type Reason string
+
+const (
+	OutOfRange     Reason = "out_of_range"    // span outside the text and origin not found nearby
+	OriginMismatch Reason = "origin_mismatch" // text at the span is not Origin and Origin is not nearby
+	Overlap        Reason = "overlap"         // span overlaps an edit that wins under the policy
+)
+
+// Window is how far (in runes) from the claimed start Apply looks for
+// Origin when the span does not match it.
+const Window = 32
+
+// Edit replaces the runes [Start, End) of a text with Text.
+type Edit struct {
+	Start, End int    // rune offsets
+	Origin     string // expected text at [Start, End); "" skips the check
+	Text       string // replacement
+	NoReplace  bool   // only validate and repair the span (e.g. no suggestion)
+}
+
+// Outcome is what happened to one edit.
+type Outcome struct {
+	Start, End int    // final span (repaired when Moved)
+	Moved      bool   // the span was relocated to where Origin was found
+	Dropped    Reason // non-empty when the edit was not applied
+}
+
+// Result is the outcome of Apply.
+type Result struct {
+	Text     string
+	Outcomes []Outcome // one per edit, in input order
+}
+
+// Apply validates edits against text and applies those that survive.
+//
+// An edit whose span is out of range, or whose Origin does not match the
+// text at its span, is moved to the occurrence of Origin nearest to Start
+// within Window runes; without one it is dropped.
+//
+// Overlaps are resolved deterministically: edits are taken by start, then
+// longest span first, then input order, and an edit overlapping one already
+// taken is dropped. NoReplace edits never overlap anything.
+func Apply(text string, edits []Edit) Result {
+	runes := []rune(text)
+	out := Result{Outcomes: make([]Outcome, len(edits))}
+
+	var live []int
+	for i, e := range edits {
+		o := &out.Outcomes[i]
+		o.Start, o.End = e.Start, e.End
+		switch {
+		case e.Start < 0 || e.End < e.Start || e.End > len(runes):
+			o.Dropped = OutOfRange
+		case e.Origin != "" && string(runes[e.Start:e.End]) != e.Origin:
+			o.Dropped = OriginMismatch
+		}
+		if o.Dropped != "" && e.Origin != "" {
+			if at := nearest(runes, []rune(e.Origin), e.Start, Window); at >= 0 {
+				o.Start, o.End = at, at+len([]rune(e.Origin))
+				o.Moved, o.Dropped = true, ""
+			}
+		}
+		if o.Dropped == "" && !e.NoReplace {
+			live = append(live, i)
+		}
+	}
+
+	sort.SliceStable(live, func(a, b int) bool {
+		oa, ob := out.Outcomes[live[a]], out.Outcomes[live[b]]
+		if oa.Start != ob.Start {
+			return oa.Start < ob.Start
+		}
+		return oa.End-oa.Start > ob.End-ob.Start
+	})
+
+	var b strings.Builder
+	b.Grow(len(text))
+	pos := 0
+	for _, i := range live {
+		o := &out.Outcomes[i]
+		if o.Start < pos {
+			o.Dropped = Overlap
+			continue
+		}
+		b.WriteString(string(runes[pos:o.Start]))
+		b.WriteString(edits[i].Text)
+		pos = o.End
+	}
+	b.WriteString(string(runes[pos:]))
+	out.Text = b.String()
+	return out
+}
+
+// Nearest returns the start of the occurrence of origin in text closest to
+// the rune offset at (earlier wins a tie), or -1 if origin does not occur.
+func Nearest(text, origin []rune, at int) int {
+	return nearest(text, origin, at, len(text))
+}
+
+// nearest is Nearest limited to occurrences within window runes of at.
+func nearest(runes, origin []rune, at, window int) int {
+	if len(origin) == 0 || len(origin) > len(runes) {
+		return -1
+	}
+	at = min(max(at, 0), len(runes)-len(origin))
+	for d := 0; d <= window; d++ {
+		lo, hi := at-d, at+d
+		if lo < 0 && hi+len(origin) > len(runes) {
+			break
+		}
+		if lo >= 0 && equal(runes[lo:lo+len(origin)], origin) {
+			return lo
+		}
+		if d > 0 && hi+len(origin) <= len(runes) && equal(runes[hi:hi+len(origin)], origin) {
+			return hi
+		}
+	}
+	return -1
+}
+
+func equal(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
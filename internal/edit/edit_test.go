@@ -0,0 +1,91 @@
+package edit
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	const text = "나는 학교에 갔다. 그리고 됬다."
+	tests := []struct {
+		name  string
+		edits []Edit
+		want  string
+		outs  []Outcome
+	}{
+		{
+			name:  "plain",
+			edits: []Edit{{Start: 15, End: 17, Origin: "됬다", Text: "됐다"}},
+			want:  "나는 학교에 갔다. 그리고 됐다.",
+			outs:  []Outcome{{Start: 15, End: 17}},
+		},
+		{
+			name:  "drifted offsets are repaired",
+			edits: []Edit{{Start: 12, End: 14, Origin: "됬다", Text: "됐다"}},
+			want:  "나는 학교에 갔다. 그리고 됐다.",
+			outs:  []Outcome{{Start: 15, End: 17, Moved: true}},
+		},
+		{
+			name:  "out of range is repaired from origin",
+			edits: []Edit{{Start: 40, End: 42, Origin: "됬다", Text: "됐다"}},
+			want:  "나는 학교에 갔다. 그리고 됐다.",
+			outs:  []Outcome{{Start: 15, End: 17, Moved: true}},
+		},
+		{
+			name:  "out of range without origin",
+			edits: []Edit{{Start: 40, End: 42, Text: "x"}},
+			want:  text,
+			outs:  []Outcome{{Start: 40, End: 42, Dropped: OutOfRange}},
+		},
+		{
+			name:  "origin not found",
+			edits: []Edit{{Start: 0, End: 2, Origin: "너는", Text: "우리는"}},
+			want:  text,
+			outs:  []Outcome{{Start: 0, End: 2, Dropped: OriginMismatch}},
+		},
+		{
+			name: "overlap: earlier start wins",
+			edits: []Edit{
+				{Start: 3, End: 9, Origin: "학교에 갔다", Text: "학교에 갔었다"},
+				{Start: 0, End: 6, Origin: "나는 학교에", Text: "난 학교에"},
+			},
+			want: "난 학교에 갔다. 그리고 됬다.",
+			outs: []Outcome{{Start: 3, End: 9, Dropped: Overlap}, {Start: 0, End: 6}},
+		},
+		{
+			name: "overlap: same start, longer wins",
+			edits: []Edit{
+				{Start: 3, End: 5, Origin: "학교", Text: "대학"},
+				{Start: 3, End: 6, Origin: "학교에", Text: "학교로"},
+			},
+			want: "나는 학교로 갔다. 그리고 됬다.",
+			outs: []Outcome{{Start: 3, End: 5, Dropped: Overlap}, {Start: 3, End: 6}},
+		},
+		{
+			name: "no-replace edits do not overlap",
+			edits: []Edit{
+				{Start: 3, End: 6, Origin: "학교에", NoReplace: true},
+				{Start: 3, End: 5, Origin: "학교", Text: "대학"},
+			},
+			want: "나는 대학에 갔다. 그리고 됬다.",
+			outs: []Outcome{{Start: 3, End: 6}, {Start: 3, End: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Apply(text, tt.edits)
+			if got.Text != tt.want {
+				t.Errorf("Text = %q, want %q", got.Text, tt.want)
+			}
+			for i, o := range got.Outcomes {
+				if o != tt.outs[i] {
+					t.Errorf("Outcomes[%d] = %+v, want %+v", i, o, tt.outs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApply_SupplementaryPlane(t *testing.T) {
+	got := Apply("좋아👍🏽 됬다", []Edit{{Start: 5, End: 7, Origin: "됬다", Text: "됐다"}})
+	if got.Text != "좋아👍🏽 됐다" || got.Outcomes[0].Moved {
+		t.Fatalf("Apply = %q %+v", got.Text, got.Outcomes[0])
+	}
+}
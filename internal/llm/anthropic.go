@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// anthropicVersion is the Messages API version kospell is written against.
+const anthropicVersion = "2023-06-01"
+
+// anthropicMaxTokens bounds the reply; a spell-check answer for one chunk
+// fits comfortably.
+const anthropicMaxTokens = 8192
+
+type anthropicRequest struct {
+	Model     string        `json:"model"`
+	MaxTokens int           `json:"max_tokens"`
+	System    string        `json:"system,omitempty"`
+	Messages  []chatMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage,omitempty"`
+	Error *apiError `json:"error,omitempty"`
+}
+
+// anthropicMessages speaks the Anthropic Messages API. The API has no JSON
+// response format, so the schema is enforced by the system prompt and
+// Validate alone.
+type anthropicMessages struct {
+	endpoint
+}
+
+func (p *anthropicMessages) Complete(ctx context.Context, req Request) (Reply, error) {
+	system, messages := splitSystem(req.Messages)
+	var resp anthropicResponse
+	status, err := p.post(ctx, p.baseURL+"/messages",
+		http.Header{"X-Api-Key": {p.apiKey}, "Anthropic-Version": {anthropicVersion}},
+		anthropicRequest{Model: req.Model, MaxTokens: anthropicMaxTokens, System: system, Messages: messages}, &resp)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	var reply Reply
+	if u := resp.Usage; u != nil {
+		reply.Usage = Usage{PromptTokens: u.InputTokens, CompletionTokens: u.OutputTokens, TotalTokens: u.InputTokens + u.OutputTokens}
+	}
+	if resp.Error != nil {
+		return reply, fmt.Errorf("llm: API error: %s", resp.Error.Message)
+	}
+	var text strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return reply, fmt.Errorf("llm: empty content (status %d)", status)
+	}
+	reply.Content = text.String()
+	return reply, nil
+}
@@ -1,12 +1,12 @@
-// Package llm provides a spell-check backend backed by an OpenAI-compatible LLM.
+// Package llm provides a spell-check backend backed by an LLM. The wire
+// format is chosen by a Provider: OpenAI chat completions (the default),
+// the OpenAI Responses API, Anthropic Messages or Ollama's native chat.
 package llm
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -17,31 +17,79 @@ const (
 	DefaultBaseURL = "https://api.openai.com/v1"
 )
 
-// Checker sends spell-check requests to an OpenAI-compatible chat completions API.
+// Checker sends spell-check requests to an LLM through a Provider.
 type Checker struct {
-	baseURL string
-	apiKey  string
-	model   string
-	client  *http.Client
+	provider Provider
+	name     string // provider name
+	baseURL  string
+	model    string
+	prompt   *Prompt
+	meter    *Meter
+	explain  *explainCache
 }
 
-// New creates a new LLM Checker.
+// New creates a Checker for an OpenAI-compatible chat completions API.
 // Unset fields fall back to their defaults.
 func New(apiKey, model, baseURL string) *Checker {
+	c, _ := NewFor(ProviderOpenAI, apiKey, model, baseURL)
+	return c
+}
+
+// NewFor creates a Checker speaking the named provider's API (see
+// Providers). Unset model and baseURL fall back to the provider's
+// defaults.
+func NewFor(provider, apiKey, model, baseURL string) (*Checker, error) {
+	info, ok := providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("llm: unknown provider %q (allowed: %s)", provider, strings.Join(Providers(), ", "))
+	}
 	if model == "" {
-		model = DefaultModel
+		model = info.model
 	}
 	if baseURL == "" {
-		baseURL = DefaultBaseURL
+		baseURL = info.baseURL
 	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	ep := endpoint{baseURL: baseURL, apiKey: apiKey, client: &http.Client{Timeout: 60 * time.Second}}
 	return &Checker{
-		baseURL: strings.TrimRight(baseURL, "/"),
-		apiKey:  apiKey,
-		model:   model,
-		client:  &http.Client{Timeout: 60 * time.Second},
+		provider: info.new(ep),
+		name:     provider,
+		baseURL:  baseURL,
+		model:    model,
+		prompt:   DefaultPrompt,
+		meter:    NewMeter(),
+		explain:  newExplainCache(),
+	}, nil
+}
+
+// WithPrompt returns a Checker that renders its requests with p and
+// otherwise shares c's provider, endpoint, meter and caches. A nil p means
+// DefaultPrompt.
+func (c *Checker) WithPrompt(p *Prompt) *Checker {
+	if p == nil {
+		p = DefaultPrompt
 	}
+	cc := *c
+	cc.prompt = p
+	return &cc
 }
 
+// Prompt returns the prompt c renders its requests with.
+func (c *Checker) Prompt() *Prompt { return c.prompt }
+
+// Meter returns the meter c records its token usage in. Copies made by
+// WithPrompt share it.
+func (c *Checker) Meter() *Meter { return c.meter }
+
+// Model returns the model name c requests.
+func (c *Checker) Model() string { return c.model }
+
+// Provider returns the name of the API c speaks.
+func (c *Checker) Provider() string { return c.name }
+
+// BaseURL returns the API base URL c sends requests to.
+func (c *Checker) BaseURL() string { return c.baseURL }
+
 // --- response structs (LLM doesn't include distances; caller adds them) ---
 
 type Correction struct {
@@ -62,99 +110,126 @@ type Response struct {
 	Original    string  `json:"original"`
 	Corrected   string  `json:"corrected"`
 	Corrections []Chunk `json:"corrections"`
-}
-
-// --- OpenAI wire types ---
-
-type chatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type chatRequest struct {
-	Model          string            `json:"model"`
-	Messages       []chatMessage     `json:"messages"`
-	ResponseFormat map[string]string `json:"response_format"`
-}
-
-type chatChoice struct {
-	Message struct {
-		Content string `json:"content"`
-	} `json:"message"`
-}
 
-type chatResponse struct {
-	Choices []chatChoice `json:"choices"`
-	Error   *struct {
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+	// Usage is what producing the response cost, repair re-prompt
+	// included. It is filled in by Check, not by the model.
+	Usage Usage `json:"-"`
 }
 
 // Check calls the LLM and returns parsed spell-check results.
-// protectedWords are passed as 고유명사 so the LLM won't flag them.
+// protectedWords are passed as 고유명사 so the LLM won't flag them; the
+// user message is rendered with the checker's prompt template.
+//
+// The output is validated against the response schema; if it does not
+// match, the model is asked once to repair it, with the validation error,
+// before Check gives up with ErrInvalidResponse.
+//
+// Token usage is recorded in the checker's Meter; once its daily budget is
+// spent, Check returns ErrBudgetExceeded without calling the API.
 func (c *Checker) Check(ctx context.Context, text string, protectedWords []string) (*Response, error) {
-	reqBody := chatRequest{
-		Model: c.model,
-		Messages: []chatMessage{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: buildUserMessage(text, protectedWords)},
-		},
-		ResponseFormat: map[string]string{"type": "json_object"},
-	}
+	return c.CheckStream(ctx, text, protectedWords, nil)
+}
 
-	body, err := json.Marshal(reqBody)
+// CheckStream is Check, calling onItem with each correction as soon as its
+// object is complete in the streamed reply, with the offsets the model
+// wrote. Streamed items are provisional; the returned Response is
+// authoritative. An item the repair re-prompt repeats is passed only once.
+// Providers that cannot stream deliver every item when the reply arrives.
+func (c *Checker) CheckStream(ctx context.Context, text string, protectedWords []string, onItem func(Correction)) (*Response, error) {
+	if c.meter.Exceeded() {
+		return nil, ErrBudgetExceeded
+	}
+	messages, err := c.prompt.messages(text, protectedWords)
 	if err != nil {
 		return nil, err
 	}
+	var stream *itemStream
+	if onItem != nil {
+		stream = &itemStream{onItem: onItem, seen: make(map[string]bool)}
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		c.baseURL+"/chat/completions", bytes.NewReader(body))
+	var resp *Response
+	var usage Usage
+	err = c.completeValid(ctx, Request{Messages: messages, SchemaName: "spell_check", Schema: json.RawMessage(responseSchema)}, &usage, stream,
+		func(content string) (err error) {
+			resp, err = decodeResponse(content)
+			return err
+		})
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	resp.Usage = usage
+	return resp, nil
+}
 
-	resp, err := c.client.Do(req)
+// completeValid sends req and passes the reply to decode. If decode
+// rejects it, the model is shown the error and asked once to repair its
+// answer; a second rejection is ErrInvalidResponse.
+func (c *Checker) completeValid(ctx context.Context, req Request, usage *Usage, stream *itemStream, decode func(content string) error) error {
+	content, err := c.complete(ctx, req, usage, stream)
 	if err != nil {
-		return nil, fmt.Errorf("llm: request failed: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
-
-	raw, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("llm: read body: %w", err)
+	verr := decode(content)
+	if verr == nil {
+		return nil
 	}
 
-	var chatResp chatResponse
-	if err := json.Unmarshal(raw, &chatResp); err != nil {
-		return nil, fmt.Errorf("llm: decode response: %w", err)
+	req.Messages = append(req.Messages,
+		chatMessage{Role: "assistant", Content: content},
+		chatMessage{Role: "user", Content: repairPrompt(verr)},
+	)
+	if content, err = c.complete(ctx, req, usage, stream); err != nil {
+		return err
 	}
-	if chatResp.Error != nil {
-		return nil, fmt.Errorf("llm: API error: %s", chatResp.Error.Message)
+	if verr = decode(content); verr != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, verr)
 	}
-	if len(chatResp.Choices) == 0 {
-		return nil, fmt.Errorf("llm: empty choices (status %d)", resp.StatusCode)
-	}
-
-	content := stripMarkdownFence(chatResp.Choices[0].Message.Content)
+	return nil
+}
 
-	var llmResp Response
-	if err := json.Unmarshal([]byte(content), &llmResp); err != nil {
-		return nil, fmt.Errorf("llm: parse JSON output: %w\ncontent: %s", err, content)
+// complete sends one request, adding the recorded usage to usage. With a
+// stream it streams the reply through a fresh itemScanner when the
+// provider can, and scans the whole reply otherwise.
+func (c *Checker) complete(ctx context.Context, req Request, usage *Usage, stream *itemStream) (string, error) {
+	req.Model = c.model
+	var reply Reply
+	var err error
+	if sp, ok := c.provider.(StreamProvider); ok && stream != nil {
+		reply, err = sp.CompleteStream(ctx, req, stream.scanner().Write)
+	} else {
+		reply, err = c.provider.Complete(ctx, req)
+		if err == nil && stream != nil {
+			stream.scanner().Write(stripMarkdownFence(reply.Content))
+		}
 	}
-	return &llmResp, nil
+	if reply.Usage != (Usage{}) {
+		usage.Add(c.meter.Record(c.model, reply.Usage))
+	}
+	return reply.Content, err
 }
 
-func buildUserMessage(text string, protected []string) string {
-	if len(protected) == 0 {
-		return "입력:\n" + text
-	}
-	wordList, _ := json.Marshal(protected)
-	return "<고유명사>\n" + string(wordList) + "\n\n입력:\n" + text
+// itemStream passes streamed items to onItem, each distinct item once.
+type itemStream struct {
+	onItem func(Correction)
+	seen   map[string]bool
+}
+
+func (st *itemStream) scanner() *itemScanner {
+	return newItemScanner(func(raw []byte) {
+		var item Correction
+		if json.Unmarshal(raw, &item) != nil || validateItem("item", item) != nil {
+			return
+		}
+		key := fmt.Sprintf("%d:%d:%s:%q", item.Start, item.End, item.Origin, item.Suggest)
+		if st.seen[key] {
+			return
+		}
+		st.seen[key] = true
+		st.onItem(item)
+	})
 }
 
-// stripMarkdownFence removes optional ```json ... ``` wrapping from LLM output.
 func stripMarkdownFence(s string) string {
 	s = strings.TrimSpace(s)
 	if strings.HasPrefix(s, "```") {
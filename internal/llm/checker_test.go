@@ -0,0 +1,184 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const validContent = `{"original":"됬다","corrected":"됐다","corrections":[{"idx":0,"input":"됬다","items":[{"start":0,"end":2,"origin":"됬다","suggest":["됐다"],"help":"맞춤법"}]}]}`
+
+// fakeServer answers chat completions with replies in turn and records
+// every request it receives.
+func fakeServer(t *testing.T, reqs *[]chatRequest, reply func(n int, req chatRequest) (int, any)) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		*reqs = append(*reqs, req)
+		status, body := reply(len(*reqs), req)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	}))
+}
+
+func content(s string) any {
+	return map[string]any{"choices": []any{map[string]any{"message": map[string]string{"content": s}}}}
+}
+
+func TestCheck_SendsJSONSchema(t *testing.T) {
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(int, chatRequest) (int, any) { return 200, content(validContent) })
+	defer srv.Close()
+
+	if _, err := New("key", "", srv.URL).Check(context.Background(), "됬다", nil); err != nil {
+		t.Fatal(err)
+	}
+	f := reqs[0].ResponseFormat
+	if f.Type != "json_schema" || f.JSONSchema == nil || !f.JSONSchema.Strict {
+		t.Fatalf("response_format = %+v, want strict json_schema", f)
+	}
+	if !json.Valid(f.JSONSchema.Schema) {
+		t.Fatal("schema is not valid JSON")
+	}
+}
+
+func TestCheck_FallsBackToJSONObject(t *testing.T) {
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(_ int, req chatRequest) (int, any) {
+		if req.ResponseFormat.Type == "json_schema" {
+			return 400, map[string]any{"error": map[string]string{"message": "Invalid parameter: 'response_format' of type 'json_schema' is not supported with this model."}}
+		}
+		return 200, content(validContent)
+	})
+	defer srv.Close()
+
+	c := New("key", "", srv.URL)
+	for range 2 {
+		if _, err := c.Check(context.Background(), "됬다", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var types []string
+	for _, r := range reqs {
+		types = append(types, r.ResponseFormat.Type)
+	}
+	if got := strings.Join(types, ","); got != "json_schema,json_object,json_object" {
+		t.Fatalf("formats = %s; the fallback should stick after the first rejection", got)
+	}
+}
+
+func TestCheck_RepairsInvalidResponse(t *testing.T) {
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(n int, _ chatRequest) (int, any) {
+		if n == 1 {
+			return 200, content(`{"original":"됬다","corrected":"됐다","corrections":[{"idx":0,"input":"됬다","items":[{"start":0,"end":2,"origin":"됬다","suggest":[],"help":""}]}]}`)
+		}
+		return 200, content(validContent)
+	})
+	defer srv.Close()
+
+	resp, err := New("key", "", srv.URL).Check(context.Background(), "됬다", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 2 || resp.Corrections[0].Items[0].Suggest[0] != "됐다" {
+		t.Fatalf("requests = %d, resp = %+v", len(reqs), resp)
+	}
+	msgs := reqs[1].Messages
+	if len(msgs) != 4 || msgs[2].Role != "assistant" || !strings.Contains(msgs[3].Content, "corrections[0].items[0].suggest") {
+		t.Fatalf("repair prompt = %+v", msgs)
+	}
+}
+
+func TestCheck_GivesUpAfterOneRepair(t *testing.T) {
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(int, chatRequest) (int, any) { return 200, content("교정 결과는 다음과 같습니다") })
+	defer srv.Close()
+
+	_, err := New("key", "", srv.URL).Check(context.Background(), "됬다", nil)
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("err = %v, want ErrInvalidResponse", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("requests = %d, want 2", len(reqs))
+	}
+	if strings.Contains(err.Error(), "교정 결과는") {
+		t.Errorf("error leaks the raw content: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		resp Response
+		want string
+	}{
+		{"ok", Response{Corrections: []Chunk{{Items: []Correction{{Start: 0, End: 1, Origin: "a", Suggest: []string{"b"}}}}}}, ""},
+		{"no corrections", Response{}, "corrections:"},
+		{"no items", Response{Corrections: []Chunk{{}}}, "corrections[0].items:"},
+		// Offsets are only a hint; the origin is located in the text.
+		{"negative start", Response{Corrections: []Chunk{{Items: []Correction{{Start: -1, Origin: "a", Suggest: []string{"b"}}}}}}, ""},
+		{"end before start", Response{Corrections: []Chunk{{Items: []Correction{{Start: 2, End: 1, Origin: "a", Suggest: []string{"b"}}}}}}, ""},
+		{"empty origin", Response{Corrections: []Chunk{{Items: []Correction{{Start: 0, End: 1, Suggest: []string{"b"}}}}}}, ".origin:"},
+		{"too many suggestions", Response{Corrections: []Chunk{{Items: []Correction{{Origin: "a", Suggest: []string{"1", "2", "3", "4", "5"}}}}}}, ".suggest:"},
+	}
+	for _, tt := range tests {
+		err := Validate(&tt.resp)
+		if (err == nil) != (tt.want == "") || (err != nil && !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("%s: Validate() = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	frac := `{"original":"됬다","corrected":"됐다","corrections":[{"idx":0,"input":"됬다","items":[{"start":0.5,"end":2,"origin":"됬다","suggest":["됐다"],"help":""}]}]}`
+	if _, err := decodeResponse(frac); err == nil {
+		t.Error("decodeResponse accepted a fractional offset")
+	}
+}
+
+func TestCheck_RecordsUsage(t *testing.T) {
+	withUsage := func(body any, tokens int) any {
+		m := body.(map[string]any)
+		m["usage"] = map[string]int{"prompt_tokens": tokens, "completion_tokens": tokens / 2, "total_tokens": tokens + tokens/2}
+		return m
+	}
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(n int, _ chatRequest) (int, any) {
+		if n == 1 {
+			return 200, withUsage(content("{}"), 100)
+		}
+		return 200, withUsage(content(validContent), 200)
+	})
+	defer srv.Close()
+
+	c := New("key", "gpt-5-mini", srv.URL)
+	c.Meter().SetPrices(Prices{"gpt-5-mini": {Input: 1, Output: 1}})
+	resp, err := c.Check(context.Background(), "됬다", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Usage.PromptTokens != 300 || resp.Usage.CompletionTokens != 150 || resp.Usage.TotalTokens != 450 {
+		t.Fatalf("Usage = %+v, want the repair re-prompt included", resp.Usage)
+	}
+	if resp.Usage.Cost != 450.0/1e6 {
+		t.Errorf("Cost = %v", resp.Usage.Cost)
+	}
+	if got := c.Meter().Snapshot().Models["gpt-5-mini"].TotalTokens; got != 450 {
+		t.Errorf("meter total = %d, want 450", got)
+	}
+
+	c.Meter().SetDailyBudget(400)
+	if _, err := c.WithPrompt(nil).Check(context.Background(), "됬다", nil); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+	if len(reqs) != 2 {
+		t.Errorf("requests = %d; an exceeded budget must not call the API", len(reqs))
+	}
+}
@@ -0,0 +1,157 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Pair is a correction to explain: the flagged text and the suggestion
+// replacing it. Explanations are cached per Pair.
+type Pair struct {
+	Origin  string
+	Suggest string
+}
+
+// maxExplainCache bounds the explanations a Checker keeps; past it, an
+// arbitrary entry makes room for each new one.
+const maxExplainCache = 4096
+
+type explainCache struct {
+	mu sync.Mutex
+	m  map[Pair]string
+}
+
+func newExplainCache() *explainCache {
+	return &explainCache{m: make(map[Pair]string)}
+}
+
+func (c *explainCache) get(p Pair) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	help, ok := c.m[p]
+	return help, ok
+}
+
+func (c *explainCache) put(p Pair, help string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.m[p]; !ok && len(c.m) >= maxExplainCache {
+		for k := range c.m {
+			delete(c.m, k)
+			break
+		}
+	}
+	c.m[p] = help
+}
+
+const explainSystemPrompt = `당신은 한국어 어문 규범(한글 맞춤법, 띄어쓰기, 표준어 규정) 전문가입니다.
+각 교정 항목(틀린 표기 origin → 고친 표기 suggest)에 대해, 왜 그렇게 고쳐야 하는지 근거가 되는 규칙을 한두 문장으로 설명하고, 그 규칙을 따른 올바른 예문을 하나 덧붙이세요.
+- 설명은 존댓말로, 일반 사용자가 이해할 수 있게 씁니다.
+- 예문은 "예: " 뒤에 씁니다.
+- 입력의 id를 그대로 돌려주고, 모든 항목을 설명하세요.
+
+출력 형식 (반드시 이 JSON만 출력):
+{"explanations":[{"id":0,"help":"'되-' 뒤에 과거형 어미 '-었-'이 붙으면 '되었-'이고, 줄여서 '됐-'으로 씁니다. 예: 일이 잘 됐다."}]}`
+
+const explainSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["explanations"],
+  "properties": {
+    "explanations": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["id", "help"],
+        "properties": {
+          "id":   { "type": "integer", "minimum": 0 },
+          "help": { "type": "string" }
+        }
+      }
+    }
+  }
+}`
+
+type explainInput struct {
+	ID      int    `json:"id"`
+	Origin  string `json:"origin"`
+	Suggest string `json:"suggest"`
+}
+
+type explainResponse struct {
+	Explanations []struct {
+		ID   int    `json:"id"`
+		Help string `json:"help"`
+	} `json:"explanations"`
+}
+
+// Explain returns a Korean explanation of the rule behind each pair, with
+// an example. Pairs explained before come from the checker's cache; the
+// rest are sent in one prompt. Pairs the model leaves out are missing from
+// the map. Usage is what the prompt cost, zero when everything was cached;
+// once the daily budget is spent, uncached pairs get ErrBudgetExceeded.
+func (c *Checker) Explain(ctx context.Context, pairs []Pair) (map[Pair]string, Usage, error) {
+	out := make(map[Pair]string, len(pairs))
+	var todo []Pair
+	queued := make(map[Pair]bool)
+	for _, p := range pairs {
+		if help, ok := c.explain.get(p); ok {
+			out[p] = help
+		} else if !queued[p] {
+			queued[p] = true
+			todo = append(todo, p)
+		}
+	}
+	if len(todo) == 0 {
+		return out, Usage{}, nil
+	}
+	if c.meter.Exceeded() {
+		return out, Usage{}, ErrBudgetExceeded
+	}
+
+	inputs := make([]explainInput, len(todo))
+	for i, p := range todo {
+		inputs[i] = explainInput{ID: i, Origin: p.Origin, Suggest: p.Suggest}
+	}
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return out, Usage{}, err
+	}
+	req := Request{
+		Messages: []chatMessage{
+			{Role: "system", Content: explainSystemPrompt},
+			{Role: "user", Content: "교정 항목:\n" + string(data)},
+		},
+		SchemaName: "explain",
+		Schema:     json.RawMessage(explainSchema),
+	}
+
+	var usage Usage
+	err = c.completeValid(ctx, req, &usage, nil, func(content string) error {
+		var r explainResponse
+		if err := json.Unmarshal([]byte(stripMarkdownFence(content)), &r); err != nil {
+			return fmt.Errorf("not valid JSON: %v", err)
+		}
+		if r.Explanations == nil {
+			return errors.New("explanations: required array is missing")
+		}
+		for i, e := range r.Explanations {
+			if e.ID < 0 || e.ID >= len(todo) {
+				return fmt.Errorf("explanations[%d].id: must be 0 to %d, got %d", i, len(todo)-1, e.ID)
+			}
+		}
+		for _, e := range r.Explanations {
+			if help := strings.TrimSpace(e.Help); help != "" {
+				out[todo[e.ID]] = help
+				c.explain.put(todo[e.ID], help)
+			}
+		}
+		return nil
+	})
+	return out, usage, err
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExplain_BatchesAndCaches(t *testing.T) {
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(_ int, req chatRequest) (int, any) {
+		user := req.Messages[len(req.Messages)-1].Content
+		var in []explainInput
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(user, "교정 항목:\n")), &in); err != nil {
+			t.Errorf("user message %q: %v", user, err)
+		}
+		var out explainResponse
+		for _, item := range in {
+			out.Explanations = append(out.Explanations, struct {
+				ID   int    `json:"id"`
+				Help string `json:"help"`
+			}{item.ID, fmt.Sprintf("%s → %s 설명. 예: …", item.Origin, item.Suggest)})
+		}
+		data, _ := json.Marshal(out)
+		return 200, content(string(data))
+	})
+	defer srv.Close()
+
+	c := New("key", "", srv.URL)
+	a, b := Pair{"됬다", "됐다"}, Pair{"안되요", "안돼요"}
+	got, _, err := c.Explain(context.Background(), []Pair{a, b, a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 || len(got) != 2 || got[a] != "됬다 → 됐다 설명. 예: …" {
+		t.Fatalf("requests = %d, explanations = %q", len(reqs), got)
+	}
+	if f := reqs[0].ResponseFormat; f.JSONSchema == nil || f.JSONSchema.Name != "explain" {
+		t.Errorf("response_format = %+v, want the explain schema", f)
+	}
+
+	// a is cached; only the new pair is sent.
+	c2 := c.WithPrompt(nil)
+	d := Pair{"할께요", "할게요"}
+	got, _, err = c2.Explain(context.Background(), []Pair{a, d})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 2 || !strings.Contains(reqs[1].Messages[1].Content, "할께요") || strings.Contains(reqs[1].Messages[1].Content, "됬다") {
+		t.Fatalf("second prompt = %q, want only the uncached pair", reqs[1].Messages[1].Content)
+	}
+	if got[a] == "" || got[d] == "" {
+		t.Errorf("explanations = %q", got)
+	}
+
+	if _, _, err := c.Explain(context.Background(), []Pair{b, a}); err != nil || len(reqs) != 2 {
+		t.Errorf("all cached: err = %v, requests = %d, want no new request", err, len(reqs))
+	}
+}
+
+func TestExplain_RejectsUnknownID(t *testing.T) {
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(int, chatRequest) (int, any) {
+		return 200, content(`{"explanations":[{"id":3,"help":"?"}]}`)
+	})
+	defer srv.Close()
+
+	_, _, err := New("key", "", srv.URL).Explain(context.Background(), []Pair{{"됬다", "됐다"}})
+	if err == nil || !strings.Contains(err.Error(), "explanations[0].id") || len(reqs) != 2 {
+		t.Fatalf("err = %v after %d requests, want a rejected repair", err, len(reqs))
+	}
+}
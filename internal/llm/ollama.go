@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []chatMessage   `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   json.RawMessage `json:"format,omitempty"`
+}
+
+type ollamaResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error,omitempty"`
+}
+
+// ollamaChat speaks Ollama's native /api/chat, which takes the JSON schema
+// as format.
+type ollamaChat struct {
+	endpoint
+}
+
+func (p *ollamaChat) Complete(ctx context.Context, req Request) (Reply, error) {
+	var header http.Header
+	if p.apiKey != "" {
+		header = http.Header{"Authorization": {"Bearer " + p.apiKey}}
+	}
+	var resp ollamaResponse
+	if _, err := p.post(ctx, p.baseURL+"/api/chat", header,
+		ollamaRequest{Model: req.Model, Messages: req.Messages, Format: req.Schema}, &resp); err != nil {
+		return Reply{}, err
+	}
+
+	reply := Reply{Usage: Usage{
+		PromptTokens:     resp.PromptEvalCount,
+		CompletionTokens: resp.EvalCount,
+		TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+	}}
+	if resp.Error != "" {
+		return reply, fmt.Errorf("llm: API error: %s", resp.Error)
+	}
+	reply.Content = resp.Message.Content
+	return reply, nil
+}
@@ -0,0 +1,270 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// --- OpenAI chat completions ---
+
+type chatRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	ResponseFormat responseFormat `json:"response_format"`
+	Stream         bool           `json:"stream,omitempty"`
+	StreamOptions  *streamOptions `json:"stream_options,omitempty"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type responseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type jsonSchema struct {
+	Name   string          `json:"name"`
+	Strict bool            `json:"strict"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type chatChoice struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Delta struct {
+		Content string `json:"content"`
+	} `json:"delta"` // stream chunks
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type chatResponse struct {
+	Choices []chatChoice `json:"choices"`
+	Usage   *chatUsage   `json:"usage,omitempty"`
+	Error   *apiError    `json:"error,omitempty"`
+}
+
+// apiError is the error object OpenAI and Anthropic return.
+type apiError struct {
+	Message string `json:"message"`
+}
+
+// openAIChat speaks the chat completions API, which most OpenAI-compatible
+// servers implement.
+type openAIChat struct {
+	endpoint
+
+	// noSchema is set once the endpoint rejects response_format
+	// json_schema; later requests go straight to json_object.
+	noSchema atomic.Bool
+}
+
+func newOpenAIChat(ep endpoint) *openAIChat { return &openAIChat{endpoint: ep} }
+
+// Complete asks for json_schema output and falls back to json_object for
+// good when the endpoint rejects it.
+func (p *openAIChat) Complete(ctx context.Context, req Request) (Reply, error) {
+	return p.CompleteStream(ctx, req, nil)
+}
+
+// CompleteStream is Complete with stream: true; a nil onText asks for the
+// whole reply at once.
+func (p *openAIChat) CompleteStream(ctx context.Context, req Request, onText func(string)) (Reply, error) {
+	var reply Reply
+	if !p.noSchema.Load() {
+		status, err := p.post(ctx, req, &reply, responseFormat{
+			Type:       "json_schema",
+			JSONSchema: &jsonSchema{Name: req.SchemaName, Strict: true, Schema: req.Schema},
+		}, onText)
+		if err == nil || !schemaRejected(status, err) {
+			return reply, err
+		}
+		p.noSchema.Store(true)
+	}
+	_, err := p.post(ctx, req, &reply, responseFormat{Type: "json_object"}, onText)
+	return reply, err
+}
+
+func (p *openAIChat) post(ctx context.Context, req Request, reply *Reply, format responseFormat, onText func(string)) (int, error) {
+	body := chatRequest{Model: req.Model, Messages: req.Messages, ResponseFormat: format}
+	if onText != nil {
+		body.Stream, body.StreamOptions = true, &streamOptions{IncludeUsage: true}
+	}
+	resp, err := p.do(ctx, p.baseURL+"/chat/completions", http.Header{"Authorization": {"Bearer " + p.apiKey}}, body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if onText != nil && strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return resp.StatusCode, readChatStream(resp, reply, onText)
+	}
+	// Not streaming, or an error: a single JSON body.
+	var cr chatResponse
+	status, err := decodeBody(resp, &cr)
+	if err != nil {
+		return status, err
+	}
+	if err := cr.apply(reply, status); err != nil {
+		return status, err
+	}
+	if len(cr.Choices) == 0 {
+		return status, fmt.Errorf("llm: empty choices (status %d)", status)
+	}
+	reply.Content = cr.Choices[0].Message.Content
+	if onText != nil {
+		onText(reply.Content)
+	}
+	return status, nil
+}
+
+// apply adds the usage of r to reply and returns its API error, if any.
+func (r *chatResponse) apply(reply *Reply, status int) error {
+	if u := r.Usage; u != nil {
+		reply.Usage.Add(Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens, TotalTokens: u.TotalTokens})
+	}
+	if r.Error != nil {
+		return fmt.Errorf("llm: API error: %s", r.Error.Message)
+	}
+	return nil
+}
+
+// readChatStream reads server-sent chat.completion.chunk events until
+// [DONE], passing each content delta to onText.
+func readChatStream(resp *http.Response, reply *Reply, onText func(string)) error {
+	var content strings.Builder
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 4<<20)
+	for sc.Scan() {
+		data, ok := strings.CutPrefix(sc.Text(), "data:")
+		if !ok {
+			continue // blank separators, comments, event names
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk chatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("llm: decode stream chunk: %w", err)
+		}
+		if err := chunk.apply(reply, resp.StatusCode); err != nil {
+			return err
+		}
+		for _, c := range chunk.Choices {
+			if c.Delta.Content != "" {
+				content.WriteString(c.Delta.Content)
+				onText(c.Delta.Content)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("llm: read stream: %w", err)
+	}
+	reply.Content = content.String()
+	return nil
+}
+
+// schemaRejected reports whether a failed request looks like the endpoint
+// does not support response_format json_schema.
+func schemaRejected(status int, err error) bool {
+	if status != http.StatusBadRequest && status != http.StatusUnprocessableEntity {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "response_format") || strings.Contains(msg, "json_schema")
+}
+
+// --- OpenAI Responses API ---
+
+type responsesRequest struct {
+	Model        string        `json:"model"`
+	Instructions string        `json:"instructions,omitempty"`
+	Input        []chatMessage `json:"input"`
+	Text         responsesText `json:"text"`
+}
+
+type responsesText struct {
+	Format responsesFormat `json:"format"`
+}
+
+type responsesFormat struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name"`
+	Strict bool            `json:"strict"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type responsesResponse struct {
+	Output []struct {
+		Type    string `json:"type"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"output"`
+	Usage *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage,omitempty"`
+	Error *apiError `json:"error,omitempty"`
+}
+
+// openAIResponses speaks the OpenAI Responses API. System messages become
+// instructions and the schema goes in text.format.
+type openAIResponses struct {
+	endpoint
+}
+
+func (p *openAIResponses) Complete(ctx context.Context, req Request) (Reply, error) {
+	system, input := splitSystem(req.Messages)
+	var resp responsesResponse
+	status, err := p.post(ctx, p.baseURL+"/responses",
+		http.Header{"Authorization": {"Bearer " + p.apiKey}},
+		responsesRequest{
+			Model:        req.Model,
+			Instructions: system,
+			Input:        input,
+			Text:         responsesText{Format: responsesFormat{Type: "json_schema", Name: req.SchemaName, Strict: true, Schema: req.Schema}},
+		}, &resp)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	var reply Reply
+	if u := resp.Usage; u != nil {
+		reply.Usage = Usage{PromptTokens: u.InputTokens, CompletionTokens: u.OutputTokens, TotalTokens: u.TotalTokens}
+	}
+	if resp.Error != nil {
+		return reply, fmt.Errorf("llm: API error: %s", resp.Error.Message)
+	}
+	var text strings.Builder
+	for _, out := range resp.Output {
+		if out.Type != "message" {
+			continue // reasoning items etc.
+		}
+		for _, c := range out.Content {
+			if c.Type == "output_text" {
+				text.WriteString(c.Text)
+			}
+		}
+	}
+	if text.Len() == 0 {
+		return reply, fmt.Errorf("llm: empty output (status %d)", status)
+	}
+	reply.Content = text.String()
+	return reply, nil
+}
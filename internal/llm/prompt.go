@@ -0,0 +1,221 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Prompt template files live in a directory as <name>.tmpl, each with an
+// optional <name>.examples.json next to it.
+const (
+	PromptExt   = ".tmpl"
+	ExamplesExt = ".examples.json"
+)
+
+// DefaultPromptName names the built-in prompt. A <DefaultPromptName>.tmpl
+// in a prompt directory replaces it.
+const DefaultPromptName = "default"
+
+// defaultTemplate reproduces the user message kospell has always sent.
+const defaultTemplate = `{{if .Words}}<고유명사>
+{{json .Words}}
+
+{{end}}입력:
+{{.Text}}`
+
+// DefaultPrompt is used by checkers without a prompt of their own.
+var DefaultPrompt = mustParsePrompt(DefaultPromptName, defaultTemplate)
+
+// Prompt is a named prompt template. The template is a text/template
+// rendered into the user message with PromptData; house-style rules go in
+// its text. Examples are sent before the message as few-shot turns.
+//
+// The system prompt and the JSON output format are not part of the
+// template, so a template cannot break response parsing.
+type Prompt struct {
+	Name     string
+	Examples []Example
+
+	tmpl *template.Template
+}
+
+// PromptData is what a template sees.
+type PromptData struct {
+	Text  string   // the input text: {{.Text}}
+	Words []string // protected words (고유명사): {{.Words}}, {{json .Words}}, {{join .Words ", "}}
+}
+
+// Example is one few-shot pair: the input (and protected words) rendered
+// with the prompt's template, and the answer the model should give.
+type Example struct {
+	Input  string          `json:"input"`
+	Words  []string        `json:"words,omitempty"`
+	Output json.RawMessage `json:"output"` // a Response object
+}
+
+var promptFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+}
+
+// ParsePrompt parses a prompt template. The template must print {{.Text}}.
+func ParsePrompt(name, text string) (*Prompt, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("llm: prompt %q: %w", name, err)
+	}
+	p := &Prompt{Name: name, tmpl: tmpl}
+
+	const probe = "\x00kospell-text\x00"
+	out, err := p.render(probe, []string{"probe"})
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(out, probe) {
+		return nil, fmt.Errorf("llm: prompt %q: template does not print {{.Text}}", name)
+	}
+	return p, nil
+}
+
+func mustParsePrompt(name, text string) *Prompt {
+	p, err := ParsePrompt(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ParseExamples parses an examples file: a JSON array of Example whose
+// outputs must satisfy the response schema.
+func ParseExamples(data []byte) ([]Example, error) {
+	var examples []Example
+	if err := json.Unmarshal(data, &examples); err != nil {
+		return nil, err
+	}
+	for i, ex := range examples {
+		if ex.Input == "" {
+			return nil, fmt.Errorf("example %d: input is empty", i)
+		}
+		if _, err := decodeResponse(string(ex.Output)); err != nil {
+			return nil, fmt.Errorf("example %d: output: %w", i, err)
+		}
+	}
+	return examples, nil
+}
+
+// LoadPrompt reads a template file and, if examplesPath is not empty, its
+// examples file.
+func LoadPrompt(name, path, examplesPath string) (*Prompt, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParsePrompt(name, string(text))
+	if err != nil {
+		return nil, err
+	}
+	if examplesPath != "" {
+		data, err := os.ReadFile(examplesPath)
+		if err != nil {
+			return nil, err
+		}
+		if p.Examples, err = ParseExamples(data); err != nil {
+			return nil, fmt.Errorf("llm: prompt %q: %s: %w", name, examplesPath, err)
+		}
+	}
+	return p, nil
+}
+
+// Prompts maps names to prompts.
+type Prompts map[string]*Prompt
+
+// LoadPrompts loads every <name>.tmpl in dir, with <name>.examples.json
+// when present. The built-in prompt is always included under
+// DefaultPromptName unless dir overrides it.
+func LoadPrompts(dir string) (Prompts, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	ps := Prompts{DefaultPromptName: DefaultPrompt}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), PromptExt)
+		if !ok || e.IsDir() || name == "" {
+			continue
+		}
+		examples := filepath.Join(dir, name+ExamplesExt)
+		if _, err := os.Stat(examples); errors.Is(err, fs.ErrNotExist) {
+			examples = ""
+		}
+		p, err := LoadPrompt(name, filepath.Join(dir, e.Name()), examples)
+		if err != nil {
+			return nil, err
+		}
+		ps[name] = p
+	}
+	return ps, nil
+}
+
+// Lookup returns the named prompt; "" means DefaultPromptName.
+func (ps Prompts) Lookup(name string) (*Prompt, error) {
+	if name == "" {
+		name = DefaultPromptName
+	}
+	if p, ok := ps[name]; ok {
+		return p, nil
+	}
+	if name == DefaultPromptName {
+		return DefaultPrompt, nil
+	}
+	return nil, fmt.Errorf("llm: unknown prompt %q (available: %s)", name, strings.Join(ps.Names(), ", "))
+}
+
+// Names returns the prompt names in order.
+func (ps Prompts) Names() []string {
+	names := make([]string, 0, len(ps))
+	for name := range ps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *Prompt) render(text string, words []string) (string, error) {
+	var b strings.Builder
+	if err := p.tmpl.Execute(&b, PromptData{Text: text, Words: words}); err != nil {
+		return "", fmt.Errorf("llm: prompt %q: %w", p.Name, err)
+	}
+	return b.String(), nil
+}
+
+// messages builds the conversation for one check: the system prompt, the
+// few-shot examples and the rendered input.
+func (p *Prompt) messages(text string, words []string) ([]chatMessage, error) {
+	msgs := make([]chatMessage, 0, 2+2*len(p.Examples))
+	msgs = append(msgs, chatMessage{Role: "system", Content: systemPrompt})
+	for _, ex := range p.Examples {
+		in, err := p.render(ex.Input, ex.Words)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs,
+			chatMessage{Role: "user", Content: in},
+			chatMessage{Role: "assistant", Content: string(ex.Output)},
+		)
+	}
+	in, err := p.render(text, words)
+	if err != nil {
+		return nil, err
+	}
+	return append(msgs, chatMessage{Role: "user", Content: in}), nil
+}
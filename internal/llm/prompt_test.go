@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultPrompt_MatchesBuiltInMessage(t *testing.T) {
+	got, err := DefaultPrompt.render("됬다", []string{"KoSpell"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<고유명사>\n[\"KoSpell\"]\n\n입력:\n됬다"; got != want {
+		t.Fatalf("render = %q, want %q", got, want)
+	}
+	if got, _ := DefaultPrompt.render("됬다", nil); got != "입력:\n됬다" {
+		t.Fatalf("render without words = %q", got)
+	}
+}
+
+func TestParsePrompt_RequiresText(t *testing.T) {
+	if _, err := ParsePrompt("bad", "고유명사: {{join .Words \", \"}}"); err == nil {
+		t.Fatal("a template without {{.Text}} should be rejected")
+	}
+	if _, err := ParsePrompt("bad", "{{.Txt}}"); err == nil {
+		t.Fatal("a template with an unknown field should be rejected")
+	}
+}
+
+func TestParseExamples_ValidatesOutput(t *testing.T) {
+	if _, err := ParseExamples([]byte(`[{"input": "됬다", "output": {"corrections": [{"items": [{"origin": "됬다", "suggest": []}]}]}}]`)); err == nil {
+		t.Fatal("an example whose output breaks the schema should be rejected")
+	}
+}
+
+func writePromptDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"house.tmpl": "사내 규칙: 합쇼체를 쓰고 외래어는 국립국어원 표기를 따릅니다.\n" +
+			"{{if .Words}}고유명사: {{join .Words \", \"}}\n{{end}}입력:\n{{.Text}}",
+		"house.examples.json": `[{"input": "회의를 시작 하겠다", "output": ` +
+			`{"original": "회의를 시작 하겠다", "corrected": "회의를 시작하겠습니다", "corrections": [{"idx": 0, "input": "회의를 시작 하겠다", ` +
+			`"items": [{"start": 4, "end": 10, "origin": "시작 하겠다", "suggest": ["시작하겠습니다"], "help": "합쇼체"}]}]}}]`,
+		"plain.tmpl": "입력:\n{{.Text}}",
+		"notes.txt":  "not a template",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadPrompts(t *testing.T) {
+	ps, err := LoadPrompts(writePromptDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(ps.Names(), ","); got != "default,house,plain" {
+		t.Fatalf("Names = %s", got)
+	}
+	if len(ps["house"].Examples) != 1 || len(ps["plain"].Examples) != 0 {
+		t.Fatalf("examples: house=%d plain=%d", len(ps["house"].Examples), len(ps["plain"].Examples))
+	}
+	if p, err := ps.Lookup(""); err != nil || p != DefaultPrompt {
+		t.Fatalf("Lookup(\"\") = %v, %v", p, err)
+	}
+	if _, err := ps.Lookup("legal"); err == nil || !strings.Contains(err.Error(), "house") {
+		t.Fatalf("Lookup(unknown) = %v; the error should list the available prompts", err)
+	}
+}
+
+func TestCheck_WithPrompt(t *testing.T) {
+	ps, err := LoadPrompts(writePromptDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(int, chatRequest) (int, any) { return 200, content(validContent) })
+	defer srv.Close()
+
+	base := New("key", "", srv.URL)
+	house := base.WithPrompt(ps["house"])
+	if _, err := house.Check(context.Background(), "됬다", []string{"KoSpell", "Go"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base.Check(context.Background(), "됬다", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := reqs[0].Messages
+	if len(msgs) != 4 || msgs[1].Role != "user" || msgs[2].Role != "assistant" {
+		t.Fatalf("house messages = %+v, want system, example pair, input", msgs)
+	}
+	if !strings.Contains(msgs[1].Content, "입력:\n회의를 시작 하겠다") || !strings.Contains(msgs[2].Content, "시작하겠습니다") {
+		t.Errorf("example turn = %q / %q", msgs[1].Content, msgs[2].Content)
+	}
+	if want := "고유명사: KoSpell, Go\n입력:\n됬다"; !strings.HasSuffix(msgs[3].Content, want) || !strings.HasPrefix(msgs[3].Content, "사내 규칙") {
+		t.Errorf("input turn = %q", msgs[3].Content)
+	}
+
+	if msgs := reqs[1].Messages; len(msgs) != 2 || msgs[1].Content != "입력:\n됬다" {
+		t.Errorf("WithPrompt changed the base checker: %+v", msgs)
+	}
+}
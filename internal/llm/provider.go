@@ -0,0 +1,147 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// Provider names accepted by NewFor.
+const (
+	ProviderOpenAI    = "openai"    // chat completions: POST {base}/chat/completions
+	ProviderResponses = "responses" // OpenAI Responses API: POST {base}/responses
+	ProviderAnthropic = "anthropic" // Anthropic Messages: POST {base}/messages
+	ProviderOllama    = "ollama"    // Ollama native chat: POST {base}/api/chat
+)
+
+// Provider sends one conversation to an LLM API in that API's wire format.
+type Provider interface {
+	// Complete returns the assistant's reply text and the tokens it cost.
+	// Usage may be set even when err is not nil.
+	Complete(ctx context.Context, req Request) (Reply, error)
+}
+
+// StreamProvider is a Provider that can deliver the reply as it is
+// generated.
+type StreamProvider interface {
+	Provider
+	// CompleteStream is Complete, calling onText with each piece of the
+	// reply text as it arrives.
+	CompleteStream(ctx context.Context, req Request, onText func(string)) (Reply, error)
+}
+
+// Request is a provider-neutral completion request.
+type Request struct {
+	Model      string
+	Messages   []chatMessage   // roles: system, user, assistant
+	Schema     json.RawMessage // JSON schema the reply must match; adapters use it where the API can
+	SchemaName string          // name of Schema, for APIs that want one
+}
+
+// Reply is a provider-neutral completion result.
+type Reply struct {
+	Content string
+	Usage   Usage
+}
+
+// chatMessage is one turn of a conversation.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// endpoint is what every adapter needs to reach its API.
+type endpoint struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+type providerInfo struct {
+	model, baseURL string // defaults
+	new            func(endpoint) Provider
+}
+
+var providers = map[string]providerInfo{
+	ProviderOpenAI:    {DefaultModel, DefaultBaseURL, func(ep endpoint) Provider { return newOpenAIChat(ep) }},
+	ProviderResponses: {DefaultModel, DefaultBaseURL, func(ep endpoint) Provider { return &openAIResponses{ep} }},
+	ProviderAnthropic: {"claude-haiku-4-5", "https://api.anthropic.com/v1", func(ep endpoint) Provider { return &anthropicMessages{ep} }},
+	ProviderOllama:    {"qwen2.5", "http://localhost:11434", func(ep endpoint) Provider { return &ollamaChat{ep} }},
+}
+
+// Providers returns the provider names NewFor accepts.
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NeedsKey reports whether the provider requires an API key.
+func NeedsKey(provider string) bool { return provider != ProviderOllama }
+
+// splitSystem separates system messages, which several APIs take outside
+// the message list, from the conversation.
+func splitSystem(messages []chatMessage) (system string, rest []chatMessage) {
+	for _, m := range messages {
+		if m.Role == "system" {
+			if system != "" {
+				system += "\n\n"
+			}
+			system += m.Content
+			continue
+		}
+		rest = append(rest, m)
+	}
+	return system, rest
+}
+
+// post sends body as JSON to url and decodes the JSON reply into out.
+func (ep endpoint) post(ctx context.Context, url string, header http.Header, body, out any) (status int, err error) {
+	resp, err := ep.do(ctx, url, header, body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return decodeBody(resp, out)
+}
+
+// do sends body as JSON to url; the caller closes the response body.
+func (ep endpoint) do(ctx context.Context, url string, header http.Header, body any) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ep.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("llm: request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// decodeBody decodes a JSON response body into out.
+func decodeBody(resp *http.Response, out any) (status int, err error) {
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("llm: read body: %w", err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("llm: decode response (status %d): %w", resp.StatusCode, err)
+	}
+	return resp.StatusCode, nil
+}
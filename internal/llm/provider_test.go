@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeAPI serves one wire format: check inspects the decoded request body
+// and headers, and the handler replies with body.
+func fakeAPI(t *testing.T, path string, check func(r *http.Request, body map[string]any), reply any) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("path = %s, want %s", r.URL.Path, path)
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		check(r, body)
+		json.NewEncoder(w).Encode(reply)
+	}))
+}
+
+func checkProvider(t *testing.T, provider string, srv *httptest.Server, want Usage) {
+	t.Helper()
+	c, err := NewFor(provider, "key", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Check(context.Background(), "됬다", []string{"KoSpell"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := resp.Corrections[0].Items[0]; item.Origin != "됬다" || item.Suggest[0] != "됐다" {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if resp.Usage != want {
+		t.Errorf("Usage = %+v, want %+v", resp.Usage, want)
+	}
+}
+
+func TestProvider_Responses(t *testing.T) {
+	srv := fakeAPI(t, "/responses", func(r *http.Request, body map[string]any) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if body["instructions"] != systemPrompt {
+			t.Errorf("system prompt should be sent as instructions")
+		}
+		input := body["input"].([]any)
+		if len(input) != 1 || input[0].(map[string]any)["role"] != "user" {
+			t.Errorf("input = %v", input)
+		}
+		format := body["text"].(map[string]any)["format"].(map[string]any)
+		if format["type"] != "json_schema" || format["schema"] == nil {
+			t.Errorf("text.format = %v", format)
+		}
+	}, map[string]any{
+		"output": []any{
+			map[string]any{"type": "reasoning", "content": []any{}},
+			map[string]any{"type": "message", "content": []any{map[string]any{"type": "output_text", "text": validContent}}},
+		},
+		"usage": map[string]int{"input_tokens": 40, "output_tokens": 20, "total_tokens": 60},
+	})
+	defer srv.Close()
+	checkProvider(t, ProviderResponses, srv, Usage{PromptTokens: 40, CompletionTokens: 20, TotalTokens: 60})
+}
+
+func TestProvider_Anthropic(t *testing.T) {
+	srv := fakeAPI(t, "/messages", func(r *http.Request, body map[string]any) {
+		if r.Header.Get("X-Api-Key") != "key" || r.Header.Get("Anthropic-Version") == "" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if body["system"] != systemPrompt || body["max_tokens"] == nil {
+			t.Errorf("system/max_tokens missing: %v", body)
+		}
+		for _, m := range body["messages"].([]any) {
+			if m.(map[string]any)["role"] == "system" {
+				t.Errorf("system message left in messages")
+			}
+		}
+	}, map[string]any{
+		"content": []any{map[string]any{"type": "text", "text": validContent}},
+		"usage":   map[string]int{"input_tokens": 30, "output_tokens": 10},
+	})
+	defer srv.Close()
+	checkProvider(t, ProviderAnthropic, srv, Usage{PromptTokens: 30, CompletionTokens: 10, TotalTokens: 40})
+}
+
+func TestProvider_Ollama(t *testing.T) {
+	srv := fakeAPI(t, "/api/chat", func(r *http.Request, body map[string]any) {
+		if body["stream"] != false {
+			t.Errorf("stream = %v, want false", body["stream"])
+		}
+		if _, ok := body["format"].(map[string]any); !ok {
+			t.Errorf("format = %v, want the JSON schema", body["format"])
+		}
+		if msgs := body["messages"].([]any); msgs[0].(map[string]any)["role"] != "system" {
+			t.Errorf("messages = %v", msgs)
+		}
+	}, map[string]any{
+		"message":           map[string]string{"role": "assistant", "content": validContent},
+		"prompt_eval_count": 25,
+		"eval_count":        15,
+	})
+	defer srv.Close()
+	checkProvider(t, ProviderOllama, srv, Usage{PromptTokens: 25, CompletionTokens: 15, TotalTokens: 40})
+}
+
+func TestProvider_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]any{"type": "error", "error": map[string]string{"type": "authentication_error", "message": "invalid x-api-key"}})
+	}))
+	defer srv.Close()
+	c, _ := NewFor(ProviderAnthropic, "bad", "", srv.URL)
+	if _, err := c.Check(context.Background(), "됬다", nil); err == nil || err.Error() != "llm: API error: invalid x-api-key" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestNewFor(t *testing.T) {
+	if _, err := NewFor("gemini", "", "", ""); err == nil {
+		t.Fatal("unknown provider should fail")
+	}
+	c, err := NewFor(ProviderOllama, "", "", "")
+	if err != nil || c.Model() == DefaultModel || c.BaseURL() != "http://localhost:11434" {
+		t.Fatalf("ollama defaults: %v, model %q, url %q", err, c.Model(), c.BaseURL())
+	}
+}
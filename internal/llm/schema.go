@@ -0,0 +1,109 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidResponse is returned when the model's output still does not
+// match the response schema after the repair re-prompt.
+var ErrInvalidResponse = errors.New("llm: invalid response")
+
+// responseSchema is the strict JSON schema sent to providers that can
+// constrain their output with one; Validate enforces the same rules on the
+// decoded Response, since not every provider or endpoint does.
+const responseSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["original", "corrected", "corrections"],
+  "properties": {
+    "original":  { "type": "string" },
+    "corrected": { "type": "string" },
+    "corrections": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["idx", "input", "items"],
+        "properties": {
+          "idx":   { "type": "integer" },
+          "input": { "type": "string" },
+          "items": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "additionalProperties": false,
+              "required": ["start", "end", "origin", "suggest", "help"],
+              "properties": {
+                "start":   { "type": "integer" },
+                "end":     { "type": "integer" },
+                "origin":  { "type": "string" },
+                "suggest": { "type": "array", "items": { "type": "string" }, "minItems": 1, "maxItems": 4 },
+                "help":    { "type": "string" }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// maxSuggest mirrors the schema's suggest maxItems.
+const maxSuggest = 4
+
+// decodeResponse parses the model's content and validates it.
+func decodeResponse(content string) (*Response, error) {
+	var r Response
+	if err := json.Unmarshal([]byte(stripMarkdownFence(content)), &r); err != nil {
+		return nil, fmt.Errorf("not valid JSON: %v", err)
+	}
+	if err := Validate(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Validate checks r against the response schema. The error names the
+// first offending field so it can be fed back to the model.
+func Validate(r *Response) error {
+	if r.Corrections == nil {
+		return errors.New("corrections: required array is missing")
+	}
+	for i, ch := range r.Corrections {
+		if ch.Items == nil {
+			return fmt.Errorf("corrections[%d].items: required array is missing", i)
+		}
+		for j, item := range ch.Items {
+			if err := validateItem(fmt.Sprintf("corrections[%d].items[%d]", i, j), item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateItem checks one correction; path prefixes the error. Start and
+// End are only a hint for locating the origin, so any integers will do
+// (decoding already rejects anything else).
+func validateItem(path string, item Correction) error {
+	switch {
+	case item.Origin == "":
+		return fmt.Errorf("%s.origin: must not be empty", path)
+	case len(item.Suggest) == 0 || len(item.Suggest) > maxSuggest:
+		return fmt.Errorf("%s.suggest: must have 1 to %d entries, got %d", path, maxSuggest, len(item.Suggest))
+	}
+	for k, s := range item.Suggest {
+		if s == "" {
+			return fmt.Errorf("%s.suggest[%d]: must not be empty", path, k)
+		}
+	}
+	return nil
+}
+
+// repairPrompt asks the model to fix its previous answer.
+func repairPrompt(err error) string {
+	return "이전 응답이 출력 형식에 맞지 않습니다: " + err.Error() +
+		"\n같은 입력에 대해 형식을 지킨 JSON만 다시 출력하세요."
+}
@@ -0,0 +1,96 @@
+package llm
+
+// itemScanner reads a Response as JSON text arriving in arbitrary pieces
+// and reports each object of an "items" array as soon as it closes, before
+// the rest of the document is known. It tracks only what it needs: string
+// and escape state, and for each open container its kind and the key it
+// was opened under. Structural characters are ASCII, so feeding bytes of
+// split UTF-8 sequences is fine.
+type itemScanner struct {
+	emit func(raw []byte)
+
+	stack   []scanFrame
+	inStr   bool
+	escape  bool
+	isKey   bool
+	key     []byte
+	capture int    // stack depth of the item being captured, 0 if none
+	item    []byte // the captured item so far
+}
+
+type scanFrame struct {
+	object    bool
+	expectKey bool   // object: the next string is a key
+	key       string // object: last key read; array: key it is the value of
+}
+
+func newItemScanner(emit func(raw []byte)) *itemScanner {
+	return &itemScanner{emit: emit}
+}
+
+// Write feeds the next piece of the document.
+func (s *itemScanner) Write(p string) {
+	for i := 0; i < len(p); i++ {
+		s.byte(p[i])
+	}
+}
+
+func (s *itemScanner) byte(b byte) {
+	if s.capture > 0 {
+		s.item = append(s.item, b)
+	}
+	if s.inStr {
+		switch {
+		case s.escape:
+			s.escape = false
+		case b == '\\':
+			s.escape = true
+		case b == '"':
+			s.inStr = false
+			if s.isKey {
+				s.top().key = string(s.key)
+			}
+		case s.isKey:
+			s.key = append(s.key, b)
+		}
+		return
+	}
+
+	switch b {
+	case '"':
+		s.inStr = true
+		s.isKey = len(s.stack) > 0 && s.top().object && s.top().expectKey
+		s.key = s.key[:0]
+	case ':':
+		if len(s.stack) > 0 {
+			s.top().expectKey = false
+		}
+	case ',':
+		if len(s.stack) > 0 && s.top().object {
+			s.top().expectKey = true
+		}
+	case '{':
+		if s.capture == 0 && len(s.stack) > 0 && !s.top().object && s.top().key == "items" {
+			s.capture = len(s.stack) + 1
+			s.item = append(s.item[:0], b)
+		}
+		s.stack = append(s.stack, scanFrame{object: true, expectKey: true})
+	case '[':
+		var key string
+		if len(s.stack) > 0 && s.top().object {
+			key = s.top().key
+		}
+		s.stack = append(s.stack, scanFrame{key: key})
+	case '}', ']':
+		if len(s.stack) == 0 {
+			return
+		}
+		if b == '}' && s.capture == len(s.stack) {
+			s.capture = 0
+			s.emit(s.item)
+		}
+		s.stack = s.stack[:len(s.stack)-1]
+	}
+}
+
+func (s *itemScanner) top() *scanFrame { return &s.stack[len(s.stack)-1] }
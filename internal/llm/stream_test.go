@@ -0,0 +1,125 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestItemScanner(t *testing.T) {
+	doc := `{"original":"items {x}","corrected":"","corrections":[` +
+		`{"idx":0,"input":"a","items":[{"start":0,"end":2,"origin":"됬다","suggest":["됐다"],"help":"\"}{\" 따옴표 ] 괄호"}]},` +
+		`{"idx":1,"input":"b","items":[]},` +
+		`{"idx":2,"input":"c","items":[{"start":3,"end":4,"origin":"x","suggest":["y","z"],"help":"{\"items\":[{}]}"},{"start":5,"end":6,"origin":"p","suggest":["q"],"help":""}]}]}`
+
+	var got []string
+	s := newItemScanner(func(raw []byte) { got = append(got, string(raw)) })
+	for i := 0; i < len(doc); i++ { // worst case: one byte at a time
+		s.Write(doc[i : i+1])
+	}
+
+	want := []string{
+		`{"start":0,"end":2,"origin":"됬다","suggest":["됐다"],"help":"\"}{\" 따옴표 ] 괄호"}`,
+		`{"start":3,"end":4,"origin":"x","suggest":["y","z"],"help":"{\"items\":[{}]}"}`,
+		`{"start":5,"end":6,"origin":"p","suggest":["q"],"help":""}`,
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("items =\n%q\nwant\n%q", got, want)
+	}
+	for _, raw := range got {
+		if !json.Valid([]byte(raw)) {
+			t.Errorf("emitted invalid JSON %s", raw)
+		}
+	}
+}
+
+// streamServer answers chat completions as server-sent events, one chunk
+// per piece. Before sending piece n it waits for the signal of wait(n), if
+// any, so a test can check what was delivered up to that point.
+func streamServer(t *testing.T, reqs *[]chatRequest, pieces []string, wait func(n int) <-chan struct{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		*reqs = append(*reqs, req)
+		w.Header().Set("Content-Type", "text/event-stream")
+		send := func(v any) {
+			data, _ := json.Marshal(v)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			w.(http.Flusher).Flush()
+		}
+		for n, p := range pieces {
+			if ch := wait(n); ch != nil {
+				select {
+				case <-ch:
+				case <-time.After(2 * time.Second):
+					t.Errorf("piece %d: item was not delivered before the stream ended", n)
+				}
+			}
+			send(map[string]any{"choices": []any{map[string]any{"delta": map[string]string{"content": p}}}})
+		}
+		send(map[string]any{"choices": []any{}, "usage": map[string]int{"prompt_tokens": 7, "completion_tokens": 3, "total_tokens": 10}})
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func TestCheckStream(t *testing.T) {
+	// Split validContent just after the item closes.
+	cut := strings.Index(validContent, `"help":"맞춤법"}`) + len(`"help":"맞춤법"}`)
+	first := make(chan struct{})
+	var reqs []chatRequest
+	srv := streamServer(t, &reqs, []string{validContent[:cut], validContent[cut:]}, func(n int) <-chan struct{} {
+		if n == 1 {
+			return first
+		}
+		return nil
+	})
+	defer srv.Close()
+
+	var items []Correction
+	resp, err := New("key", "", srv.URL).CheckStream(context.Background(), "됬다", nil, func(item Correction) {
+		items = append(items, item)
+		if len(items) == 1 {
+			close(first)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reqs[0].Stream || reqs[0].StreamOptions == nil || !reqs[0].StreamOptions.IncludeUsage {
+		t.Errorf("request stream = %v, %+v; want stream with usage", reqs[0].Stream, reqs[0].StreamOptions)
+	}
+	if len(items) != 1 || items[0].Origin != "됬다" || items[0].Suggest[0] != "됐다" {
+		t.Fatalf("streamed items = %+v", items)
+	}
+	if len(resp.Corrections) != 1 || len(resp.Corrections[0].Items) != 1 {
+		t.Fatalf("response = %+v", resp)
+	}
+	if want := (Usage{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10}); resp.Usage != want {
+		t.Errorf("usage = %+v, want %+v", resp.Usage, want)
+	}
+}
+
+func TestCheckStream_NonStreamingReply(t *testing.T) {
+	// A server that ignores stream: true still yields the items, once the
+	// whole reply is in.
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(int, chatRequest) (int, any) { return 200, content("```json\n" + validContent + "\n```") })
+	defer srv.Close()
+
+	var n int
+	if _, err := New("key", "", srv.URL).CheckStream(context.Background(), "됬다", nil, func(Correction) { n++ }); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("streamed %d items, want 1", n)
+	}
+}
@@ -0,0 +1,155 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned by Check once the meter's daily token
+// budget is spent.
+var ErrBudgetExceeded = errors.New("llm: daily token budget exceeded")
+
+// Usage counts the tokens of one or more completions.
+type Usage struct {
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	TotalTokens      int     `json:"totalTokens"`
+	Cost             float64 `json:"cost,omitempty"` // USD; 0 when the model has no price
+}
+
+// Add adds v to u.
+func (u *Usage) Add(v Usage) {
+	u.PromptTokens += v.PromptTokens
+	u.CompletionTokens += v.CompletionTokens
+	u.TotalTokens += v.TotalTokens
+	u.Cost += v.Cost
+}
+
+// Price is a model's price in USD per million tokens.
+type Price struct {
+	Input  float64 // prompt tokens
+	Output float64 // completion tokens
+}
+
+// Cost returns what u costs at p.
+func (p Price) Cost(u Usage) float64 {
+	return (float64(u.PromptTokens)*p.Input + float64(u.CompletionTokens)*p.Output) / 1e6
+}
+
+// Prices maps model names to prices.
+type Prices map[string]Price
+
+// ParsePrices parses "model=input/output" pairs separated by commas, e.g.
+// "gpt-5-mini=0.25/2,gpt-5=1.25/10" (USD per million tokens).
+func ParsePrices(s string) (Prices, error) {
+	ps := Prices{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, rates, ok := strings.Cut(item, "=")
+		in, out, ok2 := strings.Cut(rates, "/")
+		if !ok || !ok2 || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("llm: price %q: want model=input/output", item)
+		}
+		var p Price
+		var err error
+		if p.Input, err = strconv.ParseFloat(strings.TrimSpace(in), 64); err != nil || p.Input < 0 {
+			return nil, fmt.Errorf("llm: price %q: bad input price", item)
+		}
+		if p.Output, err = strconv.ParseFloat(strings.TrimSpace(out), 64); err != nil || p.Output < 0 {
+			return nil, fmt.Errorf("llm: price %q: bad output price", item)
+		}
+		ps[strings.TrimSpace(name)] = p
+	}
+	return ps, nil
+}
+
+// Meter adds up token usage per model for the life of the process, prices
+// it, and enforces an optional per-day token budget. Days follow the
+// server's local time. It is safe for concurrent use.
+type Meter struct {
+	mu          sync.Mutex
+	prices      Prices
+	dailyTokens int
+	models      map[string]*Usage
+	day         string
+	dayTokens   int
+
+	now func() time.Time // for tests
+}
+
+// NewMeter returns a Meter with no prices and no budget.
+func NewMeter() *Meter {
+	return &Meter{models: map[string]*Usage{}, now: time.Now}
+}
+
+// SetPrices replaces the price table. Models without a price cost 0.
+func (m *Meter) SetPrices(p Prices) {
+	m.mu.Lock()
+	m.prices = p
+	m.mu.Unlock()
+}
+
+// SetDailyBudget limits the tokens spent per day; 0 means no limit.
+func (m *Meter) SetDailyBudget(tokens int) {
+	m.mu.Lock()
+	m.dailyTokens = tokens
+	m.mu.Unlock()
+}
+
+// Record adds u, spent on model, to the totals and returns it priced.
+func (m *Meter) Record(model string, u Usage) Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u.Cost = m.prices[model].Cost(u)
+	total := m.models[model]
+	if total == nil {
+		total = &Usage{}
+		m.models[model] = total
+	}
+	total.Add(u)
+	m.rollover()
+	m.dayTokens += u.TotalTokens
+	return u
+}
+
+// Exceeded reports whether today's budget is spent.
+func (m *Meter) Exceeded() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollover()
+	return m.dailyTokens > 0 && m.dayTokens >= m.dailyTokens
+}
+
+func (m *Meter) rollover() {
+	if day := m.now().Format(time.DateOnly); day != m.day {
+		m.day, m.dayTokens = day, 0
+	}
+}
+
+// MeterSnapshot is a point-in-time copy of a Meter's totals.
+type MeterSnapshot struct {
+	Models      map[string]Usage `json:"models"`                // since the process started
+	Total       Usage            `json:"total"`                 // sum of Models
+	Day         string           `json:"day"`                   // YYYY-MM-DD
+	DayTokens   int              `json:"dayTokens"`             // tokens spent today
+	DailyBudget int              `json:"dailyBudget,omitempty"` // 0: no limit
+}
+
+// Snapshot returns the current totals.
+func (m *Meter) Snapshot() MeterSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rollover()
+	s := MeterSnapshot{Models: map[string]Usage{}, Day: m.day, DayTokens: m.dayTokens, DailyBudget: m.dailyTokens}
+	for name, u := range m.models {
+		s.Models[name] = *u
+		s.Total.Add(*u)
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParsePrices(t *testing.T) {
+	ps, err := ParsePrices("gpt-5-mini=0.25/2, gpt-5 = 1.25/10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps["gpt-5-mini"] != (Price{0.25, 2}) || ps["gpt-5"] != (Price{1.25, 10}) {
+		t.Fatalf("ParsePrices = %+v", ps)
+	}
+	for _, bad := range []string{"gpt-5-mini", "gpt-5-mini=0.25", "=1/2", "m=x/2", "m=1/-2"} {
+		if _, err := ParsePrices(bad); err == nil {
+			t.Errorf("ParsePrices(%q) should fail", bad)
+		}
+	}
+}
+
+func TestMeter(t *testing.T) {
+	now := time.Date(2026, 10, 18, 23, 0, 0, 0, time.Local)
+	m := NewMeter()
+	m.now = func() time.Time { return now }
+	m.SetPrices(Prices{"gpt-5-mini": {Input: 0.25, Output: 2}})
+	m.SetDailyBudget(1500)
+
+	u := m.Record("gpt-5-mini", Usage{PromptTokens: 1000, CompletionTokens: 200, TotalTokens: 1200})
+	if want := (1000*0.25 + 200*2) / 1e6; math.Abs(u.Cost-want) > 1e-12 {
+		t.Fatalf("Cost = %v, want %v", u.Cost, want)
+	}
+	m.Record("local", Usage{PromptTokens: 100, CompletionTokens: 100, TotalTokens: 200})
+	if m.Exceeded() {
+		t.Fatal("1400 of 1500 tokens should not exceed the budget")
+	}
+	m.Record("gpt-5-mini", Usage{PromptTokens: 100, TotalTokens: 100})
+	if !m.Exceeded() {
+		t.Fatal("1500 of 1500 tokens should exceed the budget")
+	}
+
+	s := m.Snapshot()
+	if s.Total.TotalTokens != 1500 || s.Models["gpt-5-mini"].TotalTokens != 1300 || s.Models["local"].Cost != 0 {
+		t.Fatalf("Snapshot = %+v", s)
+	}
+
+	now = now.Add(2 * time.Hour) // next day
+	if m.Exceeded() {
+		t.Fatal("the budget should reset at midnight")
+	}
+	if s := m.Snapshot(); s.DayTokens != 0 || s.Total.TotalTokens != 1500 || s.Day != "2026-10-19" {
+		t.Fatalf("after midnight: %+v", s)
+	}
+}
@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Claim is a correction to verify: a rule-based backend's finding and the
+// sentence it was found in, with the flagged text marked ⟦like this⟧.
+type Claim struct {
+	Sentence string
+	Origin   string
+	Suggest  []string
+	Help     string // the backend's reason, if any
+}
+
+// Verdict is the model's judgment of one Claim.
+type Verdict struct {
+	Keep   bool
+	Reason string
+}
+
+const verifySystemPrompt = `당신은 한국어 교정 결과를 검수하는 전문가입니다.
+규칙 기반 맞춤법 검사기가 찾은 교정 항목이 주어집니다. 각 항목의 sentence에서 ⟦ ⟧로 표시한 부분이 origin이고, suggest는 검사기가 제안한 표기, help는 검사기의 설명입니다.
+문맥을 보고 그 표시 부분이 정말 고쳐야 할 오류인지 판단하세요.
+- 고유명사(사람·회사·제품·서비스 이름), 전문 용어, 외래어·영문 표기, 의도된 표현, 인용된 원문을 잘못 고치려는 경우는 keep=false.
+- 실제 맞춤법·띄어쓰기·표준어 오류이면 keep=true.
+- 판단이 어려우면 keep=true.
+- reason은 판단 근거를 한국어 한 문장으로 씁니다.
+- 입력의 id를 그대로 돌려주고, 모든 항목을 판단하세요.
+
+출력 형식 (반드시 이 JSON만 출력):
+{"verdicts":[{"id":0,"keep":false,"reason":"'카카오뱅크'는 회사 이름이므로 고치지 않습니다."}]}`
+
+const verifySchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["verdicts"],
+  "properties": {
+    "verdicts": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["id", "keep", "reason"],
+        "properties": {
+          "id":     { "type": "integer", "minimum": 0 },
+          "keep":   { "type": "boolean" },
+          "reason": { "type": "string" }
+        }
+      }
+    }
+  }
+}`
+
+type verifyInput struct {
+	ID       int      `json:"id"`
+	Sentence string   `json:"sentence"`
+	Origin   string   `json:"origin"`
+	Suggest  []string `json:"suggest"`
+	Help     string   `json:"help,omitempty"`
+}
+
+type verifyResponse struct {
+	Verdicts []struct {
+		ID     int    `json:"id"`
+		Keep   bool   `json:"keep"`
+		Reason string `json:"reason"`
+	} `json:"verdicts"`
+}
+
+// Verify asks the model, in one prompt, whether each claim is a real
+// error in its sentence. Verdicts are in the order of claims; a claim the
+// model leaves out is kept. Usage is what the prompt cost. Once the daily
+// budget is spent Verify returns ErrBudgetExceeded without calling the API.
+func (c *Checker) Verify(ctx context.Context, claims []Claim) ([]Verdict, Usage, error) {
+	if len(claims) == 0 {
+		return nil, Usage{}, nil
+	}
+	if c.meter.Exceeded() {
+		return nil, Usage{}, ErrBudgetExceeded
+	}
+
+	inputs := make([]verifyInput, len(claims))
+	for i, cl := range claims {
+		inputs[i] = verifyInput{ID: i, Sentence: cl.Sentence, Origin: cl.Origin, Suggest: cl.Suggest, Help: cl.Help}
+	}
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, Usage{}, err
+	}
+	req := Request{
+		Messages: []chatMessage{
+			{Role: "system", Content: verifySystemPrompt},
+			{Role: "user", Content: "교정 항목:\n" + string(data)},
+		},
+		SchemaName: "verify",
+		Schema:     json.RawMessage(verifySchema),
+	}
+
+	verdicts := make([]Verdict, len(claims))
+	var usage Usage
+	err = c.completeValid(ctx, req, &usage, nil, func(content string) error {
+		var r verifyResponse
+		if err := json.Unmarshal([]byte(stripMarkdownFence(content)), &r); err != nil {
+			return fmt.Errorf("not valid JSON: %v", err)
+		}
+		if r.Verdicts == nil {
+			return errors.New("verdicts: required array is missing")
+		}
+		for i, v := range r.Verdicts {
+			if v.ID < 0 || v.ID >= len(claims) {
+				return fmt.Errorf("verdicts[%d].id: must be 0 to %d, got %d", i, len(claims)-1, v.ID)
+			}
+		}
+		for i := range verdicts {
+			verdicts[i] = Verdict{Keep: true}
+		}
+		for _, v := range r.Verdicts {
+			verdicts[v.ID] = Verdict{Keep: v.Keep, Reason: strings.TrimSpace(v.Reason)}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, usage, err
+	}
+	return verdicts, usage, nil
+}
@@ -0,0 +1,46 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	var reqs []chatRequest
+	srv := fakeServer(t, &reqs, func(int, chatRequest) (int, any) {
+		// id 2 is left out and must be kept.
+		return 200, content(`{"verdicts":[{"id":0,"keep":false,"reason":"회사 이름입니다."},{"id":1,"keep":true,"reason":"맞춤법 오류입니다."}]}`)
+	})
+	defer srv.Close()
+
+	claims := []Claim{
+		{Sentence: "⟦카카오뱅크⟧에 갔다.", Origin: "카카오뱅크", Suggest: []string{"카카오 뱅크"}},
+		{Sentence: "일이 ⟦됬다⟧.", Origin: "됬다", Suggest: []string{"됐다"}, Help: "맞춤법 오류"},
+		{Sentence: "⟦안되요⟧.", Origin: "안되요", Suggest: []string{"안돼요"}},
+	}
+	got, _, err := New("key", "", srv.URL).Verify(context.Background(), claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Verdict{{false, "회사 이름입니다."}, {true, "맞춤법 오류입니다."}, {true, ""}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("verdicts = %+v, want %+v", got, want)
+	}
+	user := reqs[0].Messages[1].Content
+	if !strings.Contains(user, "⟦카카오뱅크⟧에 갔다.") || !strings.Contains(user, `"help":"맞춤법 오류"`) {
+		t.Errorf("prompt = %q", user)
+	}
+	if f := reqs[0].ResponseFormat; f.JSONSchema == nil || f.JSONSchema.Name != "verify" {
+		t.Errorf("response_format = %+v, want the verify schema", f)
+	}
+}
+
+func TestVerify_BudgetExceeded(t *testing.T) {
+	c := New("key", "", "http://127.0.0.1:0")
+	c.Meter().SetDailyBudget(1)
+	c.Meter().Record(c.Model(), Usage{TotalTokens: 1})
+	if _, _, err := c.Verify(context.Background(), []Claim{{Sentence: "⟦됬다⟧", Origin: "됬다", Suggest: []string{"됐다"}}}); err != ErrBudgetExceeded {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+}
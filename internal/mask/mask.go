@@ -0,0 +1,423 @@
+// Package mask hides spans the spell-check backends should never touch
+// (URLs, e-mail addresses, code, paths, hashtags, @mentions, numbers with
+// units) behind single-rune placeholders, and maps results computed on the
+// masked text back onto the original.
+//
+// Placeholders are taken from the Unicode Private Use Area, skipping any
+// rune already present in the text, so they survive chunking as one rune
+// and are left alone by every backend.
+package mask
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/model"
+	"github.com/Alfex4936/kospell/internal/util"
+)
+
+// Categories in the order they claim spans; earlier ones win overlaps
+// (a URL inside a code span stays part of the code span).
+const (
+	Code    = "code"
+	URL     = "url"
+	Email   = "email"
+	Path    = "path"
+	RelPath = "relpath"
+	Mention = "mention"
+	Hashtag = "hashtag"
+	Number  = "number"
+)
+
+// Names lists every category.
+var Names = []string{Code, URL, Email, Path, RelPath, Mention, Hashtag, Number}
+
+// Options toggles each category.
+type Options struct {
+	URL     bool `json:"url"`
+	Email   bool `json:"email"`
+	Code    bool `json:"code"`
+	Path    bool `json:"path"`
+	RelPath bool `json:"relpath"`
+	Hashtag bool `json:"hashtag"`
+	Mention bool `json:"mention"`
+	Number  bool `json:"number"`
+}
+
+// All enables every category.
+func All() Options {
+	return Options{URL: true, Email: true, Code: true, Path: true, RelPath: true, Hashtag: true, Mention: true, Number: true}
+}
+
+// Default enables the categories that are never Korean prose: URLs, e-mail
+// addresses, code and @mentions. Numbers ("3개", "10시30분") and loose
+// relative paths ("and/or", "1/2") take part in spelling and spacing, so
+// they are opt-in, as are paths and hashtags.
+func Default() Options {
+	return Options{URL: true, Email: true, Code: true, Mention: true}
+}
+
+// Parse builds Options from category names. "all" enables everything and
+// "none" (or an empty list) nothing.
+func Parse(names []string) (Options, error) {
+	var o Options
+	for _, n := range names {
+		switch strings.ToLower(strings.TrimSpace(n)) {
+		case "all":
+			o = All()
+		case "none":
+			o = Options{}
+		case URL:
+			o.URL = true
+		case Email:
+			o.Email = true
+		case Code:
+			o.Code = true
+		case Path:
+			o.Path = true
+		case RelPath:
+			o.RelPath = true
+		case Hashtag:
+			o.Hashtag = true
+		case Mention:
+			o.Mention = true
+		case Number:
+			o.Number = true
+		default:
+			return Options{}, fmt.Errorf("unknown mask category %q (allowed: %s, all, none)", n, strings.Join(Names, ", "))
+		}
+	}
+	return o, nil
+}
+
+// Enabled reports whether category is on.
+func (o Options) Enabled(category string) bool {
+	switch category {
+	case URL:
+		return o.URL
+	case Email:
+		return o.Email
+	case Code:
+		return o.Code
+	case Path:
+		return o.Path
+	case RelPath:
+		return o.RelPath
+	case Hashtag:
+		return o.Hashtag
+	case Mention:
+		return o.Mention
+	case Number:
+		return o.Number
+	}
+	return false
+}
+
+// Any reports whether at least one category is on.
+func (o Options) Any() bool {
+	return o != Options{}
+}
+
+var patterns = map[string]*regexp.Regexp{
+	Code:    regexp.MustCompile("(?s)```.*?```|`[^`\n]+`"),
+	URL:     regexp.MustCompile(`(?i)\b(?:https?|ftp)://[A-Za-z0-9\-._~:/?#\[\]@!$&'*+,;=%]+|\bwww\.[A-Za-z0-9\-._~:/?#\[\]@!$&'*+,;=%]+`),
+	Email:   regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	Path:    regexp.MustCompile(`(?:~|\.{1,2})?(?:/[A-Za-z0-9._\-]+)+/?|[A-Za-z]:\\[A-Za-z0-9._\-\\]*`),
+	RelPath: regexp.MustCompile(`[A-Za-z0-9._\-]+(?:/[A-Za-z0-9._\-]+)+/?`),
+	Mention: regexp.MustCompile(`@[A-Za-z0-9_](?:[A-Za-z0-9_.\-]*[A-Za-z0-9_])?`),
+	Hashtag: regexp.MustCompile(`#[\p{L}\p{N}_]+`),
+	Number:  regexp.MustCompile(`\d+(?:[.,:]\d+)*(?:%|[A-Za-z]+|[°℃㎎㎏㎖㎞㎡㎝㎜])?`),
+}
+
+// Span is one masked region of the original text.
+type Span struct {
+	Category    string
+	Start, End  int // byte offsets in the original text
+	Text        string
+	Placeholder rune
+}
+
+// Masked is a text with its spans replaced by placeholders.
+type Masked struct {
+	Text string // text sent to the backend
+
+	spans  []Span
+	byRune map[rune]string
+}
+
+// Apply masks text according to opts. When nothing matches, Text is the
+// input unchanged and every method is a no-op.
+func Apply(text string, opts Options) *Masked {
+	m := &Masked{Text: text}
+	if !opts.Any() {
+		return m
+	}
+
+	var spans []Span
+	for _, cat := range Names {
+		if !opts.Enabled(cat) {
+			continue
+		}
+		for _, loc := range patterns[cat].FindAllStringIndex(text, -1) {
+			start, end := loc[0], trimEnd(text, cat, loc[0], loc[1])
+			if end <= start || !standsAlone(text, cat, start) || overlaps(spans, start, end) {
+				continue
+			}
+			spans = append(spans, Span{Category: cat, Start: start, End: end, Text: text[start:end]})
+		}
+	}
+	if len(spans) == 0 {
+		return m
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+
+	m.byRune = make(map[rune]string, len(spans))
+	next := placeholderFirst
+	var b strings.Builder
+	last := 0
+	for i := range spans {
+		for next <= placeholderLast && strings.ContainsRune(text, next) {
+			next++
+		}
+		if next > placeholderLast {
+			break // out of placeholders; leave the rest unmasked
+		}
+		spans[i].Placeholder = next
+		m.byRune[next] = spans[i].Text
+		m.spans = append(m.spans, spans[i])
+		b.WriteString(text[last:spans[i].Start])
+		b.WriteRune(next)
+		last = spans[i].End
+		next++
+	}
+	b.WriteString(text[last:])
+	m.Text = b.String()
+	return m
+}
+
+const (
+	placeholderFirst = '\uE000'
+	placeholderLast  = '\uF8FF'
+)
+
+// trimEnd drops sentence punctuation glued to the end of URLs and paths
+// ("https://a.com/x." or "(see /etc/hosts)").
+func trimEnd(text, cat string, start, end int) int {
+	if cat != URL && cat != Path && cat != RelPath {
+		return end
+	}
+	for end > start {
+		c := text[end-1]
+		if strings.IndexByte(".,;:!?'", c) >= 0 {
+			end--
+			continue
+		}
+		if c == ')' && strings.Count(text[start:end], "(") < strings.Count(text[start:end], ")") {
+			end--
+			continue
+		}
+		break
+	}
+	return end
+}
+
+// standsAlone rejects matches that start in the middle of a word, such as
+// the "@b" of "a@b" or the "2" of "v2" — those belong to something else.
+func standsAlone(text, cat string, start int) bool {
+	switch cat {
+	case Mention, Hashtag, Number, Path, RelPath:
+	default:
+		return true
+	}
+	if start == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:start])
+	return !(r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '&'))
+}
+
+func overlaps(spans []Span, start, end int) bool {
+	for _, s := range spans {
+		if start < s.End && s.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// Spans returns the masked spans in text order.
+func (m *Masked) Spans() []Span {
+	return m.spans
+}
+
+// Restore replaces every placeholder in s with the text it stands for.
+func (m *Masked) Restore(s string) string {
+	if len(m.byRune) == 0 || !m.hasPlaceholder(s) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if orig, ok := m.byRune[r]; ok {
+			b.WriteString(orig)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (m *Masked) hasPlaceholder(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		_, ok := m.byRune[r]
+		return ok
+	}) >= 0
+}
+
+// placeholders returns the placeholders in s, in order.
+func (m *Masked) placeholders(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if _, ok := m.byRune[r]; ok {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// DropReason is the model.Dropped reason of corrections RestoreResult
+// removes for editing a masked span.
+const DropReason = "masked"
+
+// RestoreResult maps res, computed on m.Text, back onto the original text:
+// chunk inputs, origins, suggestions and help are restored and rune offsets
+// (chunk offsets and dropped corrections included) are remapped; Doc spans
+// are left for the caller to recompute with model.Locate. Suggestions that
+// drop or reorder a placeholder would edit a masked span, so they are
+// removed, and a correction left without suggestions moves to res.Dropped
+// with DropReason. It returns the number of corrections removed; when it is
+// non-zero res.Corrected still reflects them and must be rebuilt.
+func (m *Masked) RestoreResult(res *model.Result) int {
+	if res == nil || len(m.spans) == 0 {
+		return 0
+	}
+
+	doc := []rune(res.Original)
+	res.Original = m.Restore(res.Original)
+	res.Corrected = m.Restore(res.Corrected)
+	res.CharCount = utf8.RuneCountInString(res.Original)
+
+	// Dropped offsets are relative to the masked input of their chunk.
+	inputs := make(map[int][]rune, len(res.Corrections))
+	for _, c := range res.Corrections {
+		inputs[c.Idx] = []rune(c.Input)
+	}
+	inputOf := func(idx int) []rune {
+		if in, ok := inputs[idx]; ok {
+			return in
+		}
+		return doc
+	}
+	for i := range res.Dropped {
+		m.restoreDropped(&res.Dropped[i], inputOf(res.Dropped[i].Idx))
+	}
+
+	var removed []model.Dropped
+	newCorrs := res.Corrections[:0]
+	for _, c := range res.Corrections {
+		c.Offset = m.remap(doc, c.Offset)
+		runes := inputs[c.Idx]
+		kept := c.Items[:0]
+		for _, item := range c.Items {
+			d := model.Dropped{Idx: c.Idx, Correction: item, Reason: DropReason}
+			d.Suggest = append([]string(nil), item.Suggest...)
+			if !m.restoreItem(&item, runes) {
+				m.restoreDropped(&d, runes)
+				removed = append(removed, d)
+				continue
+			}
+			kept = append(kept, item)
+		}
+		c.Items = kept
+		c.Input = m.Restore(c.Input)
+		if len(c.Items) > 0 {
+			newCorrs = append(newCorrs, c)
+		}
+	}
+	res.Corrections = newCorrs
+	res.ErrorCount -= len(removed)
+	res.Dropped = append(res.Dropped, removed...)
+	return len(removed)
+}
+
+// restoreDropped maps d, whose offsets are rune offsets in input, back onto
+// the original text.
+func (m *Masked) restoreDropped(d *model.Dropped, input []rune) {
+	d.Start = m.remap(input, d.Start)
+	d.End = m.remap(input, d.End)
+	d.Origin = m.Restore(d.Origin)
+	d.Help = m.Restore(d.Help)
+	for j, s := range d.Suggest {
+		d.Suggest[j] = m.Restore(s)
+	}
+}
+
+// RestoreItem is RestoreResult for a single correction whose offsets are
+// rune offsets in m.Text. It reports false when the correction would edit a
+// masked span and must be dropped.
+func (m *Masked) RestoreItem(item *model.Correction) bool {
+	if len(m.spans) == 0 {
+		return true
+	}
+	return m.restoreItem(item, []rune(m.Text))
+}
+
+func (m *Masked) restoreItem(item *model.Correction, input []rune) bool {
+	item.Start = m.remap(input, item.Start)
+	item.End = m.remap(input, item.End)
+	item.Help = m.Restore(item.Help)
+
+	want := m.placeholders(item.Origin)
+	touched := want != ""
+	for _, s := range item.Suggest {
+		touched = touched || m.hasPlaceholder(s)
+	}
+	if !touched {
+		return true
+	}
+
+	item.Origin = m.Restore(item.Origin)
+	suggest := item.Suggest[:0]
+	for _, s := range item.Suggest {
+		if m.placeholders(s) == want {
+			suggest = append(suggest, m.Restore(s))
+		}
+	}
+	if len(suggest) == 0 {
+		return false
+	}
+	item.Suggest = suggest
+	item.Distances = make([]int, len(suggest))
+	for i, s := range suggest {
+		item.Distances[i] = util.Levenshtein(item.Origin, s)
+	}
+	return true
+}
+
+// remap converts a rune offset in masked text (a chunk input or the whole
+// document) to one in the restored text. Out-of-range offsets are clamped.
+func (m *Masked) remap(input []rune, off int) int {
+	if off <= 0 {
+		return off
+	}
+	n := off
+	for _, r := range input[:min(off, len(input))] {
+		if orig, ok := m.byRune[r]; ok {
+			n += utf8.RuneCountInString(orig) - 1
+		}
+	}
+	return n
+}
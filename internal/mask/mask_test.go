@@ -0,0 +1,207 @@
+package mask
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func TestApply_Categories(t *testing.T) {
+	tests := []struct {
+		cat  string
+		in   string
+		want string
+	}{
+		{URL, "문서는 https://example.com/a?b=1 에 있습니다.", "https://example.com/a?b=1"},
+		{URL, "링크(https://example.com/x).", "https://example.com/x"},
+		{Email, "문의는 dev.team@example.co.kr 로 주세요", "dev.team@example.co.kr"},
+		{Code, "명령어 `go test ./...` 를 실행", "`go test ./...`"},
+		{Code, "예시:\n```\n안녕 하세요\n```\n끝", "```\n안녕 하세요\n```"},
+		{Path, "설정은 /etc/kospell/config.yaml 을 보세요", "/etc/kospell/config.yaml"},
+		{Path, "./cmd/main.go 파일", "./cmd/main.go"},
+		{RelPath, "cmd/main.go 파일", "cmd/main.go"},
+		{Hashtag, "오늘의 #맞춤법 공부", "#맞춤법"},
+		{Mention, "@alfex 님 확인 부탁", "@alfex"},
+		{Number, "무게는 10kg 이고 용량은 3.5GB", "10kg"},
+		{Number, "성공률 100% 달성", "100%"},
+	}
+	for _, tt := range tests {
+		m := Apply(tt.in, All())
+		var got []string
+		for _, s := range m.Spans() {
+			if s.Category == tt.cat {
+				got = append(got, s.Text)
+			}
+		}
+		if len(got) == 0 || got[0] != tt.want {
+			t.Errorf("Apply(%q) %s spans = %q, want first %q", tt.in, tt.cat, got, tt.want)
+		}
+		if m.Restore(m.Text) != tt.in {
+			t.Errorf("Restore(Apply(%q)) = %q", tt.in, m.Restore(m.Text))
+		}
+	}
+}
+
+func TestApply_NotMidWord(t *testing.T) {
+	m := Apply("KoSpell2 와 a#b 그리고 x@y", Options{Number: true, Hashtag: true, Mention: true})
+	if len(m.Spans()) != 0 {
+		t.Fatalf("spans = %+v, want none", m.Spans())
+	}
+}
+
+func TestApply_DefaultLeavesProse(t *testing.T) {
+	in := "2024년 3개를 10시30분에 샀다. and/or 1/2 /etc/hosts #태그"
+	if m := Apply(in, Default()); len(m.Spans()) != 0 {
+		t.Fatalf("spans = %+v, want none", m.Spans())
+	}
+	// Without relpath, path needs a leading / that starts a word.
+	if m := Apply("and/or 1/2", Options{Path: true}); len(m.Spans()) != 0 {
+		t.Fatalf("path spans = %+v, want none", m.Spans())
+	}
+}
+
+func TestApply_Toggle(t *testing.T) {
+	in := "메일 dev@example.com 과 10kg"
+	m := Apply(in, Options{Email: true})
+	if len(m.Spans()) != 1 || m.Spans()[0].Category != Email {
+		t.Fatalf("spans = %+v, want only the email", m.Spans())
+	}
+	if m := Apply(in, Options{}); m.Text != in || len(m.Spans()) != 0 {
+		t.Fatalf("Apply with no categories changed the text: %q", m.Text)
+	}
+}
+
+func TestApply_SkipsPlaceholdersInText(t *testing.T) {
+	in := "\uE000 그리고 10kg"
+	m := Apply(in, Options{Number: true})
+	if len(m.Spans()) != 1 || m.Spans()[0].Placeholder == '\uE000' {
+		t.Fatalf("spans = %+v", m.Spans())
+	}
+}
+
+func TestRestoreResult(t *testing.T) {
+	in := "자세한건 https://example.com/guide 를 참고 하세요. 무게는10kg 입니다."
+	m := Apply(in, Options{URL: true, Number: true})
+	if len(m.Spans()) != 2 {
+		t.Fatalf("spans = %+v", m.Spans())
+	}
+
+	url := string(m.Spans()[0].Placeholder)
+	num := string(m.Spans()[1].Placeholder)
+	find := func(s string) int {
+		return utf8.RuneCountInString(m.Text[:strings.Index(m.Text, s)])
+	}
+
+	items := []model.Correction{
+		// plain fix after the URL: offsets shift by len(url)-1
+		{Start: find("참고 하세요"), End: find("참고 하세요") + 6, Origin: "참고 하세요", Suggest: []string{"참고하세요"}, Distances: []int{1}},
+		// spacing around a placeholder: kept and restored
+		{Start: find(url + " 를"), End: find(url+" 를") + 3, Origin: url + " 를", Suggest: []string{url + "를"}, Distances: []int{1}},
+		// suggestion edits the masked number: dropped
+		{Start: find("무게는" + num), End: find("무게는"+num) + 4, Origin: "무게는" + num, Suggest: []string{"무게는 10 kg"}, Distances: []int{3}},
+	}
+	res := &model.Result{
+		Original:    m.Text,
+		Corrected:   m.Text,
+		ErrorCount:  len(items),
+		Corrections: []model.Chunk{{Idx: 0, Input: m.Text, Items: items}},
+	}
+
+	if removed := m.RestoreResult(res); removed != 1 {
+		t.Fatalf("RestoreResult() removed %d, want 1", removed)
+	}
+	if res.Original != in || res.Corrections[0].Input != in {
+		t.Fatalf("original not restored: %q", res.Original)
+	}
+	if res.ErrorCount != 2 || len(res.Corrections[0].Items) != 2 {
+		t.Fatalf("got %d errors, items %+v", res.ErrorCount, res.Corrections[0].Items)
+	}
+	runes := []rune(in)
+	for _, item := range res.Corrections[0].Items {
+		if got := string(runes[item.Start:item.End]); got != item.Origin {
+			t.Errorf("offsets [%d,%d) = %q, want origin %q", item.Start, item.End, got, item.Origin)
+		}
+	}
+	if got := res.Corrections[0].Items[1].Suggest[0]; got != "https://example.com/guide를" {
+		t.Errorf("restored suggestion = %q", got)
+	}
+}
+
+func TestRestoreResult_Dropped(t *testing.T) {
+	in := "메일 dev@example.com 로 보내 주세요. 무게는 10kg 입니다."
+	m := Apply(in, Options{Email: true, Number: true})
+	num := string(m.Spans()[1].Placeholder)
+	find := func(s string) int {
+		return utf8.RuneCountInString(m.Text[:strings.Index(m.Text, s)])
+	}
+
+	res := &model.Result{
+		Original:  m.Text,
+		Corrected: m.Text,
+		Corrections: []model.Chunk{{Idx: 0, Input: m.Text, Items: []model.Correction{
+			{Start: find(num), End: find(num) + 1, Origin: num, Suggest: []string{"10 kg"}},
+		}}},
+		// dropped by the backend after the e-mail placeholder
+		Dropped: []model.Dropped{{Idx: 0, Correction: model.Correction{
+			Start: find("보내 주세요"), End: find("보내 주세요") + 6, Origin: "보내 주세요", Suggest: []string{"보내주세요"},
+		}, Reason: "overlap"}},
+		ErrorCount: 1,
+	}
+
+	if removed := m.RestoreResult(res); removed != 1 {
+		t.Fatalf("RestoreResult() removed %d, want 1", removed)
+	}
+	if len(res.Dropped) != 2 {
+		t.Fatalf("Dropped = %+v, want the backend's and the masked one", res.Dropped)
+	}
+	runes := []rune(in)
+	for _, d := range res.Dropped {
+		if got := string(runes[d.Start:d.End]); got != d.Origin {
+			t.Errorf("dropped %s [%d,%d) = %q, want origin %q", d.Reason, d.Start, d.End, got, d.Origin)
+		}
+	}
+	if d := res.Dropped[1]; d.Reason != DropReason || d.Origin != "10kg" || d.Suggest[0] != "10 kg" {
+		t.Errorf("masked drop = %+v", d)
+	}
+}
+
+func TestParse(t *testing.T) {
+	o, err := Parse([]string{"url", " Email "})
+	if err != nil || o != (Options{URL: true, Email: true}) {
+		t.Fatalf("Parse() = %+v, %v", o, err)
+	}
+	if o, _ := Parse([]string{"all"}); o != All() {
+		t.Fatalf("Parse(all) = %+v", o)
+	}
+	if o, _ := Parse([]string{"none"}); o.Any() {
+		t.Fatalf("Parse(none) = %+v", o)
+	}
+	if _, err := Parse([]string{"phone"}); err == nil {
+		t.Fatal("Parse should reject unknown categories")
+	}
+}
+
+func TestRestoreResult_ChunkOffset(t *testing.T) {
+	in := "링크 https://example.com/a 확인. 그리고 됬다."
+	m := Apply(in, Options{URL: true})
+	k := strings.Index(m.Text, "그리고")
+	second := m.Text[k:]
+	res := &model.Result{
+		Original:   m.Text,
+		Corrected:  m.Text,
+		ErrorCount: 1,
+		Corrections: []model.Chunk{{
+			Idx: 1, Input: second, Offset: utf8.RuneCountInString(m.Text[:k]),
+			Items: []model.Correction{{Start: 4, End: 6, Origin: "됬다", Suggest: []string{"됐다"}}},
+		}},
+	}
+	m.RestoreResult(res)
+
+	ch := res.Corrections[0]
+	doc := []rune(res.Original)
+	if got := string(doc[ch.Offset+ch.Items[0].Start : ch.Offset+ch.Items[0].End]); got != "됬다" {
+		t.Fatalf("chunk offset %d points at %q, want 됬다", ch.Offset, got)
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+// Locate sets Doc on every correction in res from its chunk's Offset.
+// Offsets past the end of Original are clamped to it.
+func Locate(res *Result) {
+	if res == nil || len(res.Corrections) == 0 {
+		return
+	}
+
+	// byteAt[i] is the byte offset of rune i; lineStart holds the rune
+	// offset at which each line begins.
+	byteAt := make([]int, 0, len(res.Original)+1)
+	lineStart := []int{0}
+	for i, r := range res.Original {
+		byteAt = append(byteAt, i)
+		if r == '\n' {
+			lineStart = append(lineStart, len(byteAt))
+		}
+	}
+	byteAt = append(byteAt, len(res.Original))
+
+	position := func(off int) (line, column int) {
+		lo, hi := 0, len(lineStart)-1
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if lineStart[mid] <= off {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		return lo + 1, off - lineStart[lo] + 1
+	}
+	clamp := func(off int) int {
+		return min(max(off, 0), len(byteAt)-1)
+	}
+
+	for ci := range res.Corrections {
+		ch := &res.Corrections[ci]
+		for i := range ch.Items {
+			item := &ch.Items[i]
+			start, end := clamp(ch.Offset+item.Start), clamp(ch.Offset+item.End)
+			s := &Span{Start: start, End: end, StartByte: byteAt[start], EndByte: byteAt[end]}
+			s.Line, s.Column = position(start)
+			s.EndLine, s.EndColumn = position(end)
+			item.Doc = s
+		}
+	}
+}
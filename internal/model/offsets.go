@@ -0,0 +1,75 @@
+package model
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Offsets selects the offset variants AddOffsets fills in next to the rune
+// offsets every correction carries.
+type Offsets struct {
+	UTF16 bool `json:"utf16,omitempty"` // startUtf16/endUtf16 (JavaScript, Java)
+	Byte  bool `json:"byte,omitempty"`  // startByte/endByte (Go, Rust)
+}
+
+// Any reports whether any variant is selected.
+func (o Offsets) Any() bool { return o.UTF16 || o.Byte }
+
+// AddOffsets converts each correction's rune offsets into the variants
+// selected by o, relative to its chunk's Input like Start/End. When o.UTF16
+// is set, Doc spans get UTF-16 offsets into Original as well. Characters
+// outside the Basic Multilingual Plane (emoji, rare Hanja) count as two
+// UTF-16 code units and four bytes.
+func AddOffsets(res *Result, o Offsets) {
+	if res == nil || !o.Any() {
+		return
+	}
+	var doc *offsetTable
+	for ci := range res.Corrections {
+		ch := &res.Corrections[ci]
+		t := newOffsetTable(ch.Input)
+		for i := range ch.Items {
+			item := &ch.Items[i]
+			if o.UTF16 {
+				item.StartUTF16, item.EndUTF16 = t.utf16(item.Start), t.utf16(item.End)
+				if item.Doc != nil {
+					if doc == nil {
+						doc = newOffsetTable(res.Original)
+					}
+					item.Doc.StartUTF16, item.Doc.EndUTF16 = doc.utf16(item.Doc.Start), doc.utf16(item.Doc.End)
+				}
+			}
+			if o.Byte {
+				item.StartByte, item.EndByte = t.byte(item.Start), t.byte(item.End)
+			}
+		}
+	}
+}
+
+// offsetTable maps rune offsets in a string to byte and UTF-16 offsets.
+type offsetTable struct {
+	bytes, units []int // indexed by rune offset, one past the last rune included
+}
+
+func newOffsetTable(s string) *offsetTable {
+	n := utf8.RuneCountInString(s)
+	t := &offsetTable{bytes: make([]int, 0, n+1), units: make([]int, 0, n+1)}
+	u := 0
+	for i, r := range s {
+		t.bytes = append(t.bytes, i)
+		t.units = append(t.units, u)
+		if utf16.RuneLen(r) == 2 {
+			u += 2
+		} else {
+			u++ // invalid UTF-8 decodes to U+FFFD, one unit
+		}
+	}
+	t.bytes = append(t.bytes, len(s))
+	t.units = append(t.units, u)
+	return t
+}
+
+func (t *offsetTable) clamp(off int) int { return min(max(off, 0), len(t.bytes)-1) }
+
+func (t *offsetTable) byte(off int) *int  { v := t.bytes[t.clamp(off)]; return &v }
+func (t *offsetTable) utf16(off int) *int { v := t.units[t.clamp(off)]; return &v }
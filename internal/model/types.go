@@ -2,31 +2,93 @@ package model
 
 // Result is JSON-serialisable as-is.
 type Result struct {
-	Original     string  `json:"original"`     // 원본 텍스트
-	Corrected    string  `json:"corrected"`    // 교정 결과 텍스트
-	EditDistance int     `json:"editDistance"` // Levenshtein(original, corrected)
-	CharCount    int     `json:"charCount"`    // UTF-8 rune length
-	ChunkCount   int     `json:"chunkCount"`   // ≤ 300 어절 chunks
-	Corrections  []Chunk `json:"corrections"`  // nil if no errors
-	ErrorCount   int     `json:"errorCount"`   // total number of detected errors
+	Original     string    `json:"original"`             // 원본 텍스트
+	Corrected    string    `json:"corrected"`            // 교정 결과 텍스트
+	EditDistance int       `json:"editDistance"`         // Levenshtein(original, corrected)
+	CharCount    int       `json:"charCount"`            // UTF-8 rune length
+	ChunkCount   int       `json:"chunkCount"`           // ≤ 300 어절 chunks
+	Corrections  []Chunk   `json:"corrections"`          // nil if no errors
+	ErrorCount   int       `json:"errorCount"`           // total number of detected errors
+	Dropped      []Dropped `json:"dropped,omitempty"`    // corrections that could not be applied
+	Suppressed   []Dropped `json:"suppressed,omitempty"` // corrections the verify stage judged false positives
+	Meta         *Meta     `json:"meta,omitempty"`       // backend and LLM stage bookkeeping
+}
+
+// Meta records how a backend's raw answer was post-processed.
+type Meta struct {
+	RepairedOffsets int `json:"repairedOffsets"` // LLM spans moved to where their origin is
+	DroppedOrigins  int `json:"droppedOrigins"`  // LLM corrections whose origin is not in the text
+
+	Explained int    `json:"explained,omitempty"` // corrections whose help the LLM wrote (explain stage)
+	Verified  int    `json:"verified,omitempty"`  // corrections the LLM judged in context (verify stage)
+	Usage     *Usage `json:"usage,omitempty"`     // LLM tokens spent on the result
+}
+
+// Usage counts the LLM tokens a result cost.
+type Usage struct {
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	TotalTokens      int     `json:"totalTokens"`
+	Cost             float64 `json:"cost,omitempty"` // USD, when the model has a configured price
+}
+
+// Add adds v to u.
+func (u *Usage) Add(v Usage) {
+	u.PromptTokens += v.PromptTokens
+	u.CompletionTokens += v.CompletionTokens
+	u.TotalTokens += v.TotalTokens
+	u.Cost += v.Cost
+}
+
+// Dropped is a correction removed from Corrections because it could not be
+// applied to the text, or, in Result.Suppressed, because the verify stage
+// judged it a false positive.
+type Dropped struct {
+	Idx int `json:"idx"` // chunk the correction came from
+	Correction
+	Reason string `json:"reason"` // out_of_range | origin_mismatch | overlap | masked, or the verifier's reason
 }
 
 // Chunk corresponds to one 300-어절 POST.
 type Chunk struct {
-	Idx   int          `json:"idx"`
-	Input string       `json:"input"`
-	Items []Correction `json:"items"`
+	Idx    int          `json:"idx"`
+	Input  string       `json:"input"`
+	Offset int          `json:"offset"` // rune offset of Input in Result.Original
+	Items  []Correction `json:"items"`
 }
 
 // Correction represents a single error span.
 type Correction struct {
-	Start     int      `json:"start"`                // rune offsets
-	End       int      `json:"end"`                  // rune offsets
+	Start     int      `json:"start"`                // rune offsets in Chunk.Input
+	End       int      `json:"end"`                  // rune offsets in Chunk.Input
 	Origin    string   `json:"origin"`               // wrong slice
 	Suggest   []string `json:"suggest"`              // ≥1 candidate
 	Distances []int    `json:"distances"`            // Levenshtein(origin, suggest[i])
 	Help      string   `json:"help,omitempty"`       // optional HTML
-	ErrorType string   `json:"error_type,omitempty"` // spelling | spacing | standard | statistical | unknown
+	ErrorType string   `json:"error_type,omitempty"` // spelling | spacing | standard | statistical | style | unknown
+	Severity  string   `json:"severity,omitempty"`   // error | warning | info (user dictionary rules only)
+	Doc       *Span    `json:"doc,omitempty"`        // position in Result.Original
+
+	// Optional offset variants of Start/End, set by AddOffsets.
+	StartUTF16 *int `json:"startUtf16,omitempty"` // UTF-16 code units
+	EndUTF16   *int `json:"endUtf16,omitempty"`
+	StartByte  *int `json:"startByte,omitempty"` // UTF-8 bytes
+	EndByte    *int `json:"endByte,omitempty"`
+}
+
+// Span is a correction's position in the whole document.
+type Span struct {
+	Start     int `json:"start"`     // rune offset
+	End       int `json:"end"`       // rune offset, exclusive
+	StartByte int `json:"startByte"` // UTF-8 byte offset
+	EndByte   int `json:"endByte"`
+	Line      int `json:"line"`   // 1-based
+	Column    int `json:"column"` // 1-based, in runes
+	EndLine   int `json:"endLine"`
+	EndColumn int `json:"endColumn"` // exclusive
+
+	StartUTF16 *int `json:"startUtf16,omitempty"` // UTF-16 code units, set by AddOffsets
+	EndUTF16   *int `json:"endUtf16,omitempty"`
 }
 
 // RawCorrection is the raw format from server before we transform it.
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// WriteCheckstyle writes files as Checkstyle XML. Every input file is listed,
+// including clean ones, so consumers can tell "checked" from "skipped".
+func WriteCheckstyle(w io.Writer, files []File) error {
+	rep := checkstyleReport{Version: "4.3"}
+	for _, f := range files {
+		cf := checkstyleFile{Name: f.Path}
+		for _, fd := range Findings(f.Result) {
+			cf.Errors = append(cf.Errors, checkstyleError{
+				Line:     fd.Line,
+				Column:   fd.Column,
+				Severity: checkstyleSeverity(fd.Level),
+				Message:  fd.Message,
+				Source:   ToolName + "." + fd.RuleID,
+			})
+		}
+		rep.Files = append(rep.Files, cf)
+	}
+	return writeXML(w, rep)
+}
+
+func checkstyleSeverity(level string) string {
+	if level == "note" {
+		return "info"
+	}
+	return level
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
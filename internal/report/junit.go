@@ -0,0 +1,80 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes files as JUnit XML: one test suite per file and one
+// test case per rule, failing when the rule produced findings in that file.
+func WriteJUnit(w io.Writer, files []File) error {
+	root := junitSuites{Name: ToolName}
+	for _, f := range files {
+		suite := junitSuite{Name: f.Path}
+
+		byRule := map[string][]Finding{}
+		var order []string
+		for _, fd := range Findings(f.Result) {
+			if _, ok := byRule[fd.RuleID]; !ok {
+				order = append(order, fd.RuleID)
+			}
+			byRule[fd.RuleID] = append(byRule[fd.RuleID], fd)
+		}
+
+		if len(order) == 0 {
+			suite.Cases = []junitCase{{Name: "clean", ClassName: ToolName}}
+		}
+		for _, id := range order {
+			fds := byRule[id]
+			var body strings.Builder
+			for _, fd := range fds {
+				fmt.Fprintf(&body, "%s:%d:%d: %s\n", f.Path, fd.Line, fd.Column, fd.Message)
+			}
+			suite.Cases = append(suite.Cases, junitCase{
+				Name:      id,
+				ClassName: ToolName,
+				Failures: []junitFailure{{
+					Message: fmt.Sprintf("%d %s error(s)", len(fds), id),
+					Type:    id,
+					Body:    body.String(),
+				}},
+			})
+			suite.Failures++
+		}
+
+		suite.Tests = len(suite.Cases)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Suites = append(root.Suites, suite)
+	}
+	return writeXML(w, root)
+}
@@ -0,0 +1,151 @@
+// Package report renders kospell results in formats understood by code
+// review and CI tooling (SARIF, Checkstyle XML, JUnit XML).
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+// ToolName is reported as the producing tool in every format.
+const ToolName = "kospell"
+
+// File is the check result for a single input file.
+type File struct {
+	Path   string        // path as shown to the user ("<stdin>" for piped input)
+	Result *model.Result // nil results are rendered as clean files
+}
+
+// Writer renders a set of per-file results.
+type Writer func(w io.Writer, files []File) error
+
+// Writers maps a format name to its writer.
+var Writers = map[string]Writer{
+	"sarif":      WriteSARIF,
+	"checkstyle": WriteCheckstyle,
+	"junit":      WriteJUnit,
+}
+
+// Finding is one correction with its position resolved against the
+// whole document.
+type Finding struct {
+	RuleID    string
+	Level     string // error | warning | note
+	Message   string
+	Origin    string
+	Suggest   []string
+	Line      int // 1-based
+	Column    int // 1-based, in runes
+	EndLine   int
+	EndColumn int // exclusive
+}
+
+// Findings flattens res into document-positioned findings, in chunk order.
+// Positions come from each correction's Doc span, or from its chunk's
+// Offset for results that were never located.
+func Findings(res *model.Result) []Finding {
+	if res == nil || len(res.Corrections) == 0 {
+		return nil
+	}
+
+	var idx *lineIndex
+	var out []Finding
+	for _, ch := range res.Corrections {
+		for _, item := range ch.Items {
+			f := Finding{
+				RuleID:  ruleID(item.ErrorType),
+				Level:   level(item),
+				Message: message(item),
+				Origin:  item.Origin,
+				Suggest: item.Suggest,
+			}
+			if d := item.Doc; d != nil {
+				f.Line, f.Column, f.EndLine, f.EndColumn = d.Line, d.Column, d.EndLine, d.EndColumn
+			} else {
+				if idx == nil {
+					li := newLineIndex(res.Original)
+					idx = &li
+				}
+				f.Line, f.Column = idx.position(ch.Offset + item.Start)
+				f.EndLine, f.EndColumn = idx.position(ch.Offset + item.End)
+			}
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func ruleID(errorType string) string {
+	if errorType == "" {
+		return "unknown"
+	}
+	return errorType
+}
+
+func level(item model.Correction) string {
+	switch item.Severity {
+	case "error", "warning":
+		return item.Severity
+	case "info":
+		return "note"
+	}
+	switch item.ErrorType {
+	case "spelling":
+		return "error"
+	case "spacing":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// message prefers the upstream help text and falls back to a plain
+// "origin → suggestion" description.
+func message(item model.Correction) string {
+	help := strings.TrimSpace(item.Help)
+	if len(item.Suggest) == 0 {
+		if help == "" {
+			return fmt.Sprintf("%q", item.Origin)
+		}
+		return help
+	}
+	fix := fmt.Sprintf("%q → %q", item.Origin, item.Suggest[0])
+	if help == "" {
+		return fix
+	}
+	return fix + ": " + help
+}
+
+// lineIndex converts rune offsets into 1-based line/column pairs.
+type lineIndex struct {
+	starts []int // rune offset at which each line begins
+}
+
+func newLineIndex(s string) lineIndex {
+	starts := []int{0}
+	n := 0
+	for _, r := range s {
+		n++
+		if r == '\n' {
+			starts = append(starts, n)
+		}
+	}
+	return lineIndex{starts: starts}
+}
+
+func (li lineIndex) position(offset int) (line, column int) {
+	// binary search for the last line starting at or before offset
+	lo, hi := 0, len(li.starts)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if li.starts[mid] <= offset {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo + 1, offset - li.starts[lo] + 1
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func sampleResult() *model.Result {
+	original := "첫 줄입니다.\n됬습니다 안녕 하세요"
+	return &model.Result{
+		Original:   original,
+		ErrorCount: 2,
+		Corrections: []model.Chunk{{
+			Idx:   0,
+			Input: original,
+			Items: []model.Correction{
+				{Start: 8, End: 12, Origin: "됬습니다", Suggest: []string{"됐습니다"}, Help: "맞춤법 오류", ErrorType: "spelling"},
+				{Start: 13, End: 19, Origin: "안녕 하세요", Suggest: []string{"안녕하세요"}, Help: "띄어쓰기 오류", ErrorType: "spacing"},
+			},
+		}},
+	}
+}
+
+func TestFindings_LineColumn(t *testing.T) {
+	fds := Findings(sampleResult())
+	if len(fds) != 2 {
+		t.Fatalf("len(findings) = %d, want 2", len(fds))
+	}
+	if fds[0].Line != 2 || fds[0].Column != 1 || fds[0].EndColumn != 5 {
+		t.Fatalf("findings[0] = %d:%d-%d, want 2:1-5", fds[0].Line, fds[0].Column, fds[0].EndColumn)
+	}
+	if fds[1].Line != 2 || fds[1].Column != 6 {
+		t.Fatalf("findings[1] = %d:%d, want 2:6", fds[1].Line, fds[1].Column)
+	}
+	if fds[0].RuleID != "spelling" || fds[0].Level != "error" {
+		t.Fatalf("findings[0] rule/level = %s/%s, want spelling/error", fds[0].RuleID, fds[0].Level)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	files := []File{{Path: "a.txt", Result: sampleResult()}, {Path: "clean.txt"}}
+	if err := WriteSARIF(&buf, files); err != nil {
+		t.Fatalf("WriteSARIF: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("version/runs = %s/%d", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 2 || len(run.Tool.Driver.Rules) != 2 {
+		t.Fatalf("results/rules = %d/%d, want 2/2", len(run.Results), len(run.Tool.Driver.Rules))
+	}
+	if got := run.Results[1].Locations[0].PhysicalLocation.Region.StartColumn; got != 6 {
+		t.Fatalf("startColumn = %d, want 6", got)
+	}
+}
+
+func TestWriteCheckstyleAndJUnit(t *testing.T) {
+	files := []File{{Path: "a.txt", Result: sampleResult()}, {Path: "clean.txt"}}
+
+	var cs bytes.Buffer
+	if err := WriteCheckstyle(&cs, files); err != nil {
+		t.Fatalf("WriteCheckstyle: %v", err)
+	}
+	var rep checkstyleReport
+	if err := xml.Unmarshal(cs.Bytes(), &rep); err != nil {
+		t.Fatalf("invalid checkstyle XML: %v", err)
+	}
+	if len(rep.Files) != 2 || len(rep.Files[0].Errors) != 2 || rep.Files[0].Errors[1].Source != "kospell.spacing" {
+		t.Fatalf("unexpected checkstyle report: %+v", rep)
+	}
+
+	var ju bytes.Buffer
+	if err := WriteJUnit(&ju, files); err != nil {
+		t.Fatalf("WriteJUnit: %v", err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(ju.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid JUnit XML: %v", err)
+	}
+	if suites.Tests != 3 || suites.Failures != 2 {
+		t.Fatalf("tests/failures = %d/%d, want 3/2", suites.Tests, suites.Failures)
+	}
+	if !strings.Contains(ju.String(), "a.txt:2:1:") {
+		t.Fatalf("failure body missing position:\n%s", ju.String())
+	}
+}
+
+func TestFindings_UsesDocSpan(t *testing.T) {
+	res := sampleResult()
+	res.Corrections[0].Items[0].Doc = &model.Span{Line: 7, Column: 3, EndLine: 7, EndColumn: 7}
+	fds := Findings(res)
+	if fds[0].Line != 7 || fds[0].Column != 3 || fds[0].EndColumn != 7 {
+		t.Fatalf("findings[0] = %d:%d-%d, want the Doc span 7:3-7", fds[0].Line, fds[0].Column, fds[0].EndColumn)
+	}
+	if fds[1].Line != 2 || fds[1].Column != 6 {
+		t.Fatalf("findings[1] = %d:%d, want 2:6 from the chunk offset", fds[1].Line, fds[1].Column)
+	}
+}
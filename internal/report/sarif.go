@@ -0,0 +1,162 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// --- SARIF 2.1.0 subset ---
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+var ruleDescriptions = map[string]string{
+	"spelling":    "맞춤법 오류",
+	"spacing":     "띄어쓰기 오류",
+	"standard":    "표준어 의심",
+	"statistical": "통계적 교정",
+	"style":       "사용자 사전 규칙",
+	"unknown":     "기타 오류",
+}
+
+// WriteSARIF writes files as a single-run SARIF 2.1.0 log.
+// Columns are counted in Unicode code points, matching kospell's rune offsets.
+func WriteSARIF(w io.Writer, files []File) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           ToolName,
+			InformationURI: "https://github.com/Alfex4936/kospell",
+		}},
+		ColumnKind: "unicodeCodePoints",
+		Results:    []sarifResult{},
+	}
+
+	rules := map[string]struct{}{}
+	for _, f := range files {
+		loc := sarifArtifactLocation{URI: f.Path}
+		for _, fd := range Findings(f.Result) {
+			rules[fd.RuleID] = struct{}{}
+			region := sarifRegion{
+				StartLine:   fd.Line,
+				StartColumn: fd.Column,
+				EndLine:     fd.EndLine,
+				EndColumn:   fd.EndColumn,
+			}
+			r := sarifResult{
+				RuleID:    fd.RuleID,
+				Level:     fd.Level,
+				Message:   sarifMessage{Text: fd.Message},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: loc, Region: region}}},
+			}
+			if len(fd.Suggest) > 0 {
+				r.Fixes = []sarifFix{{
+					Description: sarifMessage{Text: fd.Suggest[0]},
+					ArtifactChanges: []sarifArtifactChange{{
+						ArtifactLocation: loc,
+						Replacements: []sarifReplacement{{
+							DeletedRegion:   region,
+							InsertedContent: sarifMessage{Text: fd.Suggest[0]},
+						}},
+					}},
+				}}
+			}
+			run.Results = append(run.Results, r)
+		}
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	run.Tool.Driver.Rules = make([]sarifRule, 0, len(ids))
+	for _, id := range ids {
+		desc := ruleDescriptions[id]
+		if desc == "" {
+			desc = id
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: desc}})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
@@ -0,0 +1,57 @@
+package kospell
+
+import (
+	"github.com/Alfex4936/kospell/internal/edit"
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+// applyEdits rebuilds res.Corrected from res.Original by applying each
+// correction's first suggestion through the edit engine. Corrections whose
+// offsets drifted are moved to where their Origin actually is; those that
+// cannot be placed, or that lose an overlap, are moved from Corrections to
+// res.Dropped with the reason. It returns how many corrections were moved.
+func applyEdits(res *model.Result) (moved int) {
+	var edits []edit.Edit
+	for _, ch := range res.Corrections {
+		for _, item := range ch.Items {
+			e := edit.Edit{Start: ch.Offset + item.Start, End: ch.Offset + item.End, Origin: item.Origin}
+			if len(item.Suggest) > 0 {
+				e.Text = item.Suggest[0]
+			} else {
+				e.NoReplace = true
+			}
+			edits = append(edits, e)
+		}
+	}
+	r := edit.Apply(res.Original, edits)
+	res.Corrected = r.Text
+
+	k := 0
+	newCorrs := res.Corrections[:0]
+	for _, ch := range res.Corrections {
+		kept := ch.Items[:0]
+		for _, item := range ch.Items {
+			o := r.Outcomes[k]
+			k++
+			if o.Dropped != "" {
+				res.Dropped = append(res.Dropped, model.Dropped{Idx: ch.Idx, Correction: item, Reason: string(o.Dropped)})
+				continue
+			}
+			if o.Moved {
+				item.Start, item.End = o.Start-ch.Offset, o.End-ch.Offset
+				moved++
+			}
+			kept = append(kept, item)
+		}
+		ch.Items = kept
+		if len(ch.Items) > 0 {
+			newCorrs = append(newCorrs, ch)
+		}
+	}
+	res.Corrections = newCorrs
+	res.ErrorCount = 0
+	for _, ch := range res.Corrections {
+		res.ErrorCount += len(ch.Items)
+	}
+	return moved
+}
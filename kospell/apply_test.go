@@ -0,0 +1,39 @@
+package kospell
+
+import (
+	"testing"
+
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func TestApplyEdits_ReportsDropped(t *testing.T) {
+	res := &model.Result{
+		Original: "앞 문장. 잘 됬다 그리고 안되요.",
+		Corrections: []model.Chunk{{Idx: 0, Offset: 6, Input: "잘 됬다 그리고 안되요.", Items: []model.Correction{
+			{Start: 2, End: 4, Origin: "됬다", Suggest: []string{"됐다"}},
+			{Start: 0, End: 4, Origin: "잘 됬다", Suggest: []string{"잘됐다"}},  // overlaps and is longer: wins
+			{Start: 10, End: 13, Origin: "안되요", Suggest: []string{"안돼요"}}, // drifted by one
+			{Start: 0, End: 2, Origin: "없는말", Suggest: []string{"x"}},
+		}}},
+		ErrorCount: 4,
+	}
+	if moved := applyEdits(res); moved != 1 {
+		t.Errorf("moved = %d, want 1", moved)
+	}
+	if want := "앞 문장. 잘됐다 그리고 안돼요."; res.Corrected != want {
+		t.Errorf("Corrected = %q, want %q", res.Corrected, want)
+	}
+	if res.ErrorCount != 2 || len(res.Corrections[0].Items) != 2 {
+		t.Fatalf("kept %d items (ErrorCount %d), want 2", len(res.Corrections[0].Items), res.ErrorCount)
+	}
+	if it := res.Corrections[0].Items[1]; it.Start != 9 || it.End != 12 {
+		t.Errorf("repaired span = [%d,%d), want [9,12)", it.Start, it.End)
+	}
+	reasons := map[string]string{}
+	for _, d := range res.Dropped {
+		reasons[d.Origin] = d.Reason
+	}
+	if reasons["됬다"] != "overlap" || reasons["없는말"] != "origin_mismatch" || len(reasons) != 2 {
+		t.Errorf("dropped = %+v", res.Dropped)
+	}
+}
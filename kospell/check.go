@@ -7,14 +7,14 @@ import (
 	"errors"
 	"io"
 	"net/url"
-	"regexp"
 	"runtime"
-	"sort"
 	"strings"
 	"sync"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/Alfex4936/kospell/internal/chunk"
+	"github.com/Alfex4936/kospell/internal/dictmatch"
 	"github.com/Alfex4936/kospell/internal/model"
 	"github.com/Alfex4936/kospell/internal/net"
 	"github.com/Alfex4936/kospell/internal/parse"
@@ -23,17 +23,18 @@ import (
 
 // Check submits text (any length) and returns a normalized Result.
 //
-// It transparently splits input into ≤300-어절 chunks,
+// It transparently splits input into ≤300-어절 chunks of whole sentences,
 // dispatches them in parallel (bounded by GOMAXPROCS), and merges the outcome.
+// Corrected keeps the input's whitespace and line breaks byte for byte.
 //
 // ctx controls overall timeout / cancellation.
 func Check(ctx context.Context, text string) (*model.Result, error) {
-	text = strings.TrimSpace(text) // remove all whitespace before and after
 	if ctx == nil {
 		return nil, errors.New("ctx is nil")
 	}
 
-	parts := chunk.Split300(text)
+	pieces := naraPieces(text)
+	parts := pieceTexts(pieces)
 	out := make([]chunkResult, len(parts))
 
 	sem := make(chan struct{}, cap(make([]byte, 0, runtime.GOMAXPROCS(0))))
@@ -63,90 +64,89 @@ func Check(ctx context.Context, text string) (*model.Result, error) {
 		return nil, firstErr
 	}
 
-	var totalErrors int
-	for _, cr := range out {
-		totalErrors += len(cr.items)
-	}
-
 	// merge → public Result
 	res := &model.Result{
 		Original:   text,
 		CharCount:  utf8.RuneCountInString(text),
-		ErrorCount: totalErrors,
 		ChunkCount: len(parts),
 	}
+	starts := chunk.RuneStarts(text, pieces)
 	res.Corrections = make([]model.Chunk, 0, len(out))
-	for _, cr := range out {
+	for i, cr := range out {
 		if len(cr.items) > 0 {
 			res.Corrections = append(res.Corrections, model.Chunk{
-				Idx:   cr.idx,
-				Input: cr.input,
-				Items: cr.items,
+				Idx:    cr.idx,
+				Input:  cr.input,
+				Offset: starts[i] + echoOffset(parts[i], cr.input),
+				Items:  cr.items,
 			})
 		}
 	}
 
-	// build corrected text: apply first suggestion per chunk, then join
-	corrParts := make([]string, len(out))
-	for i, cr := range out {
-		corrParts[i] = applyCorrections(cr.input, cr.items)
-	}
-	res.Corrected = strings.Join(corrParts, " ")
+	// build corrected text: apply first suggestions in place, so everything
+	// between and around the chunks is kept byte for byte
+	applyEdits(res)
 	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
 
 	return res, nil
 }
 
-// applyCorrections replaces each error span with its first suggestion.
-// Applies right-to-left so earlier rune offsets stay valid.
-func applyCorrections(input string, items []model.Correction) string {
-	if len(items) == 0 {
-		return input
+// naraPieces cuts text per NaraChunking. Whitespace around the text, and
+// between chunks, is left out of every chunk.
+func naraPieces(text string) []chunk.Piece {
+	lead := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+	pieces := NaraChunking.Split(strings.TrimSpace(text))
+	for i := range pieces {
+		pieces[i].Start += lead
 	}
-	sorted := make([]model.Correction, len(items))
-	copy(sorted, items)
-	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start > sorted[j].Start })
+	return pieces
+}
 
-	runes := []rune(input)
-	for _, c := range sorted {
-		if len(c.Suggest) == 0 {
-			continue
-		}
-		repl := []rune(c.Suggest[0])
-		runes = append(runes[:c.Start], append(repl, runes[c.End:]...)...)
+func pieceTexts(pieces []chunk.Piece) []string {
+	out := make([]string, len(pieces))
+	for i, p := range pieces {
+		out[i] = p.Text
+	}
+	return out
+}
+
+// echoOffset returns the rune offset of input, the text nara echoed back,
+// within part (0 when nara returned part unchanged or rewrote it).
+func echoOffset(part, input string) int {
+	if input == part {
+		return 0
+	}
+	if k := strings.Index(part, input); k > 0 {
+		return utf8.RuneCountInString(part[:k])
 	}
-	return string(runes)
+	return 0
 }
 
 // CheckWithDict is like Check but filters out any Correction whose Origin
-// is listed in dict.
+// is listed in dict or overlaps one of its patterns, and reports dict's
+// replace/banned entries.
 func CheckWithDict(ctx context.Context, text string, dict *Dict) (*model.Result, error) {
 	res, err := Check(ctx, text)
-	if err != nil || dict == nil || len(dict.Words) == 0 {
+	if err != nil || dict.isEmpty() {
 		return res, err
 	}
 	filterByDict(res, dict)
 
 	// Rebuild corrected text after filtering/reordering suggestions.
-	parts := chunk.Split300(res.Original)
-	itemsByIdx := make(map[int][]model.Correction, len(res.Corrections))
-	for _, c := range res.Corrections {
-		itemsByIdx[c.Idx] = c.Items
-	}
-	corrParts := make([]string, len(parts))
-	for i, p := range parts {
-		corrParts[i] = applyCorrections(p, itemsByIdx[i])
-	}
-	res.Corrected = strings.Join(corrParts, " ")
+	applyDictRules(res, naraPieces(res.Original), dict)
+	suppressDictPatterns(res, dict)
+	applyEdits(res)
 	res.Corrected = canonicalizeByDictWords(res.Corrected, dict)
 	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
 
 	return res, nil
 }
 
 func filterByDict(res *model.Result, dict *Dict) {
-	words := normalizedDictWords(dict)
-	if len(words) == 0 {
+	m := dictMatcher(dict)
+	if m.Len() == 0 {
 		return
 	}
 
@@ -154,7 +154,7 @@ func filterByDict(res *model.Result, dict *Dict) {
 	for _, c := range res.Corrections {
 		kept := c.Items[:0]
 		for i := range c.Items {
-			if keepCorrectionForDict(&c.Items[i], words) {
+			if keepCorrectionForDict(&c.Items[i], m) {
 				kept = append(kept, c.Items[i])
 			}
 		}
@@ -168,36 +168,35 @@ func filterByDict(res *model.Result, dict *Dict) {
 	res.Corrections = newCorrs
 }
 
-func keepCorrectionForDict(item *model.Correction, words []dictWord) bool {
-	if len(words) == 0 {
+// keepCorrectionForDict reports whether item still fixes something once
+// protected words in its suggestions are restored to their canonical form
+// and any 조사/어미 the suggestion split off them is re-attached. The first
+// surviving suggestion is rewritten and moved to the front.
+func keepCorrectionForDict(item *model.Correction, m *dictmatch.Matcher) bool {
+	if m.Len() == 0 {
 		return true
 	}
 
-	originCompact := compactWhitespace(item.Origin)
-	relevant := make([]dictWord, 0, len(words))
-	for _, w := range words {
-		if strings.Contains(item.Origin, w.Canonical) || strings.Contains(originCompact, w.Compact) {
-			relevant = append(relevant, w)
-		}
-	}
+	relevant := m.Present(item.Origin)
 	if len(relevant) == 0 {
 		return true
 	}
 
 	best := -1
 	for i, s := range item.Suggest {
-		fixed := s
-		changed := false
-		for _, w := range relevant {
-			var c bool
-			fixed, c = collapseSpacesWithinWord(fixed, w.Canonical)
-			changed = changed || c
+		fixed, _ := m.Canonicalize(s, relevant)
+		fixed = rejoinDetachedSuffixes(item.Origin, fixed, m, relevant)
+		if fixed == item.Origin {
+			// The suggestion only re-spaced the protected word or split a
+			// 조사/어미 off it; nothing is left to fix.
+			continue
 		}
+		changed := fixed != s
 
-		fixedCompact := compactWhitespace(fixed)
 		ok := true
-		for _, w := range relevant {
-			if !strings.Contains(fixed, w.Canonical) && !strings.Contains(fixedCompact, w.Compact) {
+		present := m.Present(fixed)
+		for id := range relevant {
+			if _, found := present[id]; !found {
 				ok = false
 				break
 			}
@@ -226,99 +225,31 @@ func keepCorrectionForDict(item *model.Correction, words []dictWord) bool {
 	return true
 }
 
-type dictWord struct {
-	Canonical string
-	Compact   string
-}
-
 func canonicalizeByDictWords(text string, dict *Dict) string {
-	words := normalizedDictWords(dict)
-	if len(words) == 0 {
-		return text
-	}
-
-	out := text
-	for _, w := range words {
-		out, _ = collapseSpacesWithinWord(out, w.Canonical)
-	}
+	out, _ := dictMatcher(dict).Canonicalize(text, nil)
 	return out
 }
 
-func normalizedDictWords(dict *Dict) []dictWord {
-	if dict == nil || len(dict.Words) == 0 {
+// dictMatcher returns the compiled matcher over dict's protected words:
+// Words plus the canonical forms of its replacements. It is built on first
+// use and cached on dict.
+func dictMatcher(dict *Dict) *dictmatch.Matcher {
+	if dict == nil {
 		return nil
 	}
-
-	seen := make(map[string]struct{}, len(dict.Words))
-	words := make([]dictWord, 0, len(dict.Words))
-	for _, raw := range dict.Words {
-		w := normalizeDictWord(raw)
-		if w.Compact == "" {
-			continue
-		}
-		if _, ok := seen[w.Canonical]; ok {
-			continue
-		}
-		seen[w.Canonical] = struct{}{}
-		words = append(words, w)
-	}
-
-	sort.Slice(words, func(i, j int) bool {
-		li := utf8.RuneCountInString(words[i].Compact)
-		lj := utf8.RuneCountInString(words[j].Compact)
-		if li == lj {
-			return words[i].Canonical < words[j].Canonical
+	dict.matcherOnce.Do(func() {
+		raws := make([]string, 0, len(dict.Words)+len(dict.Replace))
+		raws = append(raws, dict.Words...)
+		for _, r := range dict.Replace {
+			raws = append(raws, r.To)
 		}
-		return li > lj
+		dict.matcher = newDictMatcher(raws)
 	})
-	return words
-}
-
-func normalizeDictWord(raw string) dictWord {
-	canonical := strings.Join(strings.Fields(raw), " ")
-	if canonical == "" {
-		return dictWord{}
-	}
-	return dictWord{
-		Canonical: canonical,
-		Compact:   strings.ReplaceAll(canonical, " ", ""),
-	}
-}
-
-func compactWhitespace(s string) string {
-	if s == "" {
-		return ""
-	}
-	return strings.Join(strings.Fields(s), "")
+	return dict.matcher
 }
 
-func collapseSpacesWithinWord(s, word string) (string, bool) {
-	if s == "" || word == "" {
-		return s, false
-	}
-
-	w := normalizeDictWord(word)
-	if w.Compact == "" {
-		return s, false
-	}
-
-	runes := []rune(w.Compact)
-	if len(runes) < 2 {
-		return s, false
-	}
-
-	var b strings.Builder
-	for i, r := range runes {
-		b.WriteString(regexp.QuoteMeta(string(r)))
-		if i != len(runes)-1 {
-			b.WriteString(`\s*`)
-		}
-	}
-
-	re := regexp.MustCompile(b.String())
-	out := re.ReplaceAllString(s, w.Canonical)
-	return out, out != s
-}
+// newDictMatcher builds a matcher; benchmarks replace it to count builds.
+var newDictMatcher = dictmatch.New
 
 /***----- private -----***/
 
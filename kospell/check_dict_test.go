@@ -31,14 +31,14 @@ func TestCanonicalizeByDictWords_UsesCanonicalSpacing(t *testing.T) {
 }
 
 func TestKeepCorrectionForDict_RewritesSuggestionToCanonical(t *testing.T) {
-	words := normalizedDictWords(NewDict("목제솜틀기"))
+	m := dictMatcher(NewDict("목제솜틀기"))
 	item := &model.Correction{
 		Origin:    "목제 솜 틀기",
 		Suggest:   []string{"목제 솜 틀기", "목재 솜틀기"},
 		Distances: []int{0, 0},
 	}
 
-	ok := keepCorrectionForDict(item, words)
+	ok := keepCorrectionForDict(item, m)
 	if !ok {
 		t.Fatal("keepCorrectionForDict() returned false, want true")
 	}
@@ -0,0 +1,107 @@
+package kospell
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/chunk"
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+// naraCorrected mirrors how Check rebuilds Corrected from per-chunk items.
+func naraCorrected(text string, fix func(part string) []model.Correction) string {
+	pieces := naraPieces(text)
+	starts := chunk.RuneStarts(text, pieces)
+	res := &model.Result{Original: text}
+	for i, p := range pieces {
+		res.Corrections = append(res.Corrections, model.Chunk{Idx: i, Input: p.Text, Offset: starts[i], Items: fix(p.Text)})
+	}
+	applyEdits(res)
+	return res.Corrected
+}
+
+func TestNaraRejoin_RoundTrip(t *testing.T) {
+	saved := NaraChunking
+	defer func() { NaraChunking = saved }()
+	NaraChunking.Size = 4
+
+	paragraph := "오늘은 날씨가 맑다. 산책을 나갔다.\n공원에 사람이 많았다."
+	text := "\n  " + paragraph + "\n\n" + paragraph + "\n\n\n" + "- 목록 하나\n- 목록 둘\n\t\n"
+
+	none := func(string) []model.Correction { return nil }
+	if got := naraCorrected(text, none); got != text {
+		t.Fatalf("no fixes: Corrected = %q\nwant %q", got, text)
+	}
+
+	// Fix every "많았다" → "많았었다"; nothing else may change.
+	fix := func(part string) []model.Correction {
+		var items []model.Correction
+		runes := []rune(part)
+		word := []rune("많았다")
+		for i := 0; i+len(word) <= len(runes); i++ {
+			if string(runes[i:i+len(word)]) == string(word) {
+				items = append(items, model.Correction{Start: i, End: i + len(word), Origin: "많았다", Suggest: []string{"많았었다"}})
+			}
+		}
+		return items
+	}
+	want := strings.ReplaceAll(text, "많았다", "많았었다")
+	if got := naraCorrected(text, fix); got != want {
+		t.Fatalf("Corrected = %q\nwant %q", got, want)
+	}
+}
+
+func TestNaraPieces_NoSurroundingSpace(t *testing.T) {
+	for _, p := range naraPieces("  \n가 나.\n\n다 라.  \n") {
+		if strings.TrimSpace(p.Text) != p.Text {
+			t.Errorf("chunk %q carries surrounding whitespace", p.Text)
+		}
+	}
+}
+
+func TestEchoOffset(t *testing.T) {
+	if got := echoOffset("\n됬다 ", "됬다"); got != 1 {
+		t.Fatalf("echoOffset = %d, want 1", got)
+	}
+	if got := echoOffset("됬다", "됬다"); got != 0 {
+		t.Fatalf("echoOffset = %d, want 0", got)
+	}
+}
+
+func TestLocate_RepeatedChunkText(t *testing.T) {
+	line := "같은 문장이 됬다."
+	original := line + "\n" + line
+	item := func() []model.Correction {
+		return []model.Correction{{Start: 7, End: 9, Origin: "됬다", Suggest: []string{"됐다"}}}
+	}
+	res := &model.Result{
+		Original: original,
+		Corrections: []model.Chunk{
+			{Idx: 0, Input: line, Offset: 0, Items: item()},
+			{Idx: 1, Input: line, Offset: utf8.RuneCountInString(line) + 1, Items: item()},
+		},
+	}
+
+	applyEdits(res)
+	if want := strings.ReplaceAll(original, "됬다", "됐다"); res.Corrected != want {
+		t.Fatalf("Corrected = %q, want %q", res.Corrected, want)
+	}
+
+	model.Locate(res)
+	for i, ch := range res.Corrections {
+		d := ch.Items[0].Doc
+		if d == nil {
+			t.Fatalf("chunk %d: Doc not set", i)
+		}
+		if d.Line != i+1 || d.Column != 8 || d.EndLine != i+1 || d.EndColumn != 10 {
+			t.Errorf("chunk %d: position %d:%d-%d:%d, want %d:8-%d:10", i, d.Line, d.Column, d.EndLine, d.EndColumn, i+1, i+1)
+		}
+		if got := original[d.StartByte:d.EndByte]; got != "됬다" {
+			t.Errorf("chunk %d: bytes [%d,%d) = %q, want 됬다", i, d.StartByte, d.EndByte, got)
+		}
+		if got := string([]rune(original)[d.Start:d.End]); got != "됬다" {
+			t.Errorf("chunk %d: runes [%d,%d) = %q, want 됬다", i, d.Start, d.End, got)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package kospell
+
+import "github.com/Alfex4936/kospell/internal/chunk"
+
+// ChunkStrategy describes how a backend's input is cut before it is sent
+// upstream: whole sentences packed up to Size units of the backend's Limit.
+type ChunkStrategy = chunk.Strategy
+
+// Per-backend chunking. Change Size (and, for the LLM, Overlap) before the
+// first check to tune them; the Limit of each is fixed by its backend.
+var (
+	// NaraChunking: nara-speller accepts 300 어절 per request.
+	NaraChunking = ChunkStrategy{Limit: chunk.Words, Size: 300}
+	// HanspellChunking: the Naver checker accepts 300 characters.
+	HanspellChunking = ChunkStrategy{Limit: chunk.Runes, Size: hanspellMaxRunesPerChunk}
+	// HunspellChunking: the local process has no input limit, so CheckLocal
+	// sends the whole text as one piece.
+	HunspellChunking = ChunkStrategy{Limit: chunk.Unlimited}
+	// LLMChunking keeps each prompt well inside the context window; Overlap
+	// tokens of the preceding sentences are repeated as context.
+	LLMChunking = ChunkStrategy{Limit: chunk.Tokens, Size: 2000, Overlap: 200}
+)
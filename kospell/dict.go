@@ -2,20 +2,81 @@ package kospell
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Alfex4936/kospell/internal/dictmatch"
 )
 
-// Dict is a user dictionary for protecting specific terms from spell-check.
+// Dict is a user dictionary.
+//
+//   - Words are protected terms: corrections that would break them are suppressed.
+//   - Replace entries force a canonical spelling (e.g. "됬" → "됐", brand names).
+//   - Banned entries flag discouraged terms.
+//   - Patterns protect case-insensitive words and regular expressions;
+//     corrections overlapping a match are suppressed.
+//
+// Replace and Banned matches are reported as their own corrections
+// (error_type "style") even when the backend finds nothing.
+//
+// A Dict compiles its protected words into a matcher on first use; build a
+// new Dict (e.g. with MergeDicts) instead of modifying one that has been used.
 type Dict struct {
-	Words []string `json:"words"`
+	Words    []string      `json:"words"`
+	Replace  []Replacement `json:"replace,omitempty"`
+	Banned   []BannedWord  `json:"banned,omitempty"`
+	Patterns []Pattern     `json:"patterns,omitempty"`
+
+	matcherOnce  sync.Once
+	matcher      *dictmatch.Matcher
+	patternsOnce sync.Once
+	patterns     []*regexp.Regexp
 }
 
+// Replacement rewrites every occurrence of From as To.
+type Replacement struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Message  string `json:"message,omitempty"`
+	Severity string `json:"severity,omitempty"` // error | warning | info (default: error)
+}
+
+// BannedWord flags Word wherever it appears. Suggest is optional.
+type BannedWord struct {
+	Word     string   `json:"word"`
+	Suggest  []string `json:"suggest,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	Severity string   `json:"severity,omitempty"` // error | warning | info (default: warning)
+}
+
+// errInvalidDict wraps every Validate failure.
+var errInvalidDict = errors.New("dict")
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
 // NewDict creates a Dict from the given words.
 func NewDict(words ...string) *Dict {
 	return &Dict{Words: words}
 }
 
-// LoadDict reads a JSON file of the form {"words": ["목제솜틀기", ...]}.
+// LoadDict reads a JSON dictionary file. The plain {"words": ["목제솜틀기", ...]}
+// form is still accepted; "replace" and "banned" lists are optional:
+//
+//	{
+//	  "words":   ["목제솜틀기"],
+//	  "replace": [{"from": "됬", "to": "됐", "message": "'됐'이 맞습니다."}],
+//	  "banned":  [{"word": "노가다", "suggest": ["막일"], "severity": "warning"}],
+//	  "patterns": [{"word": "kafka", "ignore_case": true}, {"regex": "[A-Z]+-\\d+"}]
+//	}
 func LoadDict(path string) (*Dict, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -25,5 +86,108 @@ func LoadDict(path string) (*Dict, error) {
 	if err := json.Unmarshal(data, &d); err != nil {
 		return nil, err
 	}
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
 	return &d, nil
 }
+
+// Validate reports malformed replace/banned/patterns entries.
+func (d *Dict) Validate() error {
+	if d == nil {
+		return nil
+	}
+	for i, r := range d.Replace {
+		if strings.TrimSpace(r.From) == "" {
+			return fmt.Errorf("%w: replace[%d]: empty \"from\"", errInvalidDict, i)
+		}
+		if r.From == r.To {
+			return fmt.Errorf("%w: replace[%d]: \"from\" and \"to\" are identical", errInvalidDict, i)
+		}
+		if !validSeverity(r.Severity) {
+			return fmt.Errorf("%w: replace[%d]: invalid severity %q", errInvalidDict, i, r.Severity)
+		}
+	}
+	for i, b := range d.Banned {
+		if strings.TrimSpace(b.Word) == "" {
+			return fmt.Errorf("%w: banned[%d]: empty \"word\"", errInvalidDict, i)
+		}
+		if !validSeverity(b.Severity) {
+			return fmt.Errorf("%w: banned[%d]: invalid severity %q", errInvalidDict, i, b.Severity)
+		}
+	}
+	for i, p := range d.Patterns {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("%w: patterns[%d]: %v", errInvalidDict, i, err)
+		}
+	}
+	return nil
+}
+
+// isEmpty reports whether d has no entries of any kind.
+func (d *Dict) isEmpty() bool {
+	return d == nil || (len(d.Words) == 0 && len(d.Replace) == 0 && len(d.Banned) == 0 && len(d.Patterns) == 0)
+}
+
+func validSeverity(s string) bool {
+	switch s {
+	case "", SeverityError, SeverityWarning, SeverityInfo:
+		return true
+	default:
+		return false
+	}
+}
+
+// MergeDicts returns a new Dict holding the entries of all non-nil dicts.
+func MergeDicts(dicts ...*Dict) *Dict {
+	out := &Dict{}
+	for _, d := range dicts {
+		if d == nil {
+			continue
+		}
+		out.Words = append(out.Words, d.Words...)
+		out.Replace = append(out.Replace, d.Replace...)
+		out.Banned = append(out.Banned, d.Banned...)
+		out.Patterns = append(out.Patterns, d.Patterns...)
+	}
+	return out
+}
+
+// maxSharedMerges bounds the merges sharedMerges keeps; past it, an
+// arbitrary entry makes room for each new one.
+const maxSharedMerges = 256
+
+// sharedMerges memoises merges of shared snapshots (the DefaultDicts
+// snapshot and named store entries), so a merged Dict, and the matcher and
+// patterns it compiles, outlive the request that built it.
+var sharedMerges = &mergeCache{m: make(map[string]mergeEntry)}
+
+type mergeCache struct {
+	mu sync.Mutex
+	m  map[string]mergeEntry
+}
+
+type mergeEntry struct {
+	parts []*Dict
+	dict  *Dict
+}
+
+// merge returns MergeDicts(parts...), reusing the Dict of an earlier call
+// under key while its parts are the same snapshots. parts must not be
+// modified.
+func (c *mergeCache) merge(key string, parts ...*Dict) *Dict {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.m[key]; ok && slices.Equal(e.parts, parts) {
+		return e.dict
+	}
+	if _, ok := c.m[key]; !ok && len(c.m) >= maxSharedMerges {
+		for k := range c.m {
+			delete(c.m, k)
+			break
+		}
+	}
+	d := MergeDicts(parts...)
+	c.m[key] = mergeEntry{parts: parts, dict: d}
+	return d
+}
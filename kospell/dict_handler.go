@@ -0,0 +1,130 @@
+package kospell
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Alfex4936/kospell/internal/util"
+)
+
+// Dicts is the server-managed named dictionary store used by /v1/dicts and
+// by check requests that send "dicts". nil disables named dictionaries.
+var Dicts *DictStore
+
+type dictWordsRequest struct {
+	Words []string `json:"words"`
+}
+
+// DictsHandler handles GET /v1/dicts (list names).
+func DictsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !dictStoreEnabled(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"dicts": Dicts.Names()})
+}
+
+// DictHandler handles GET, PUT and DELETE /v1/dicts/{name}.
+func DictHandler(w http.ResponseWriter, r *http.Request) {
+	if !dictStoreEnabled(w) {
+		return
+	}
+	name := r.PathValue("name")
+
+	switch r.Method {
+	case http.MethodGet:
+		d, ok := Dicts.Get(name)
+		if !ok {
+			http.Error(w, ErrDictNotFound.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, d)
+
+	case http.MethodPut:
+		var d Dict
+		if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := Dicts.Put(name, &d); err != nil {
+			writeDictError(w, err)
+			return
+		}
+		stored, _ := Dicts.Get(name)
+		writeJSON(w, http.StatusOK, stored)
+
+	case http.MethodDelete:
+		if err := Dicts.Delete(name); err != nil {
+			writeDictError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// DictWordsHandler handles POST (add) and DELETE (remove) on
+// /v1/dicts/{name}/words with a body of {"words": [...]}.
+func DictWordsHandler(w http.ResponseWriter, r *http.Request) {
+	if !dictStoreEnabled(w) {
+		return
+	}
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req dictWordsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		d   *Dict
+		err error
+	)
+	if r.Method == http.MethodPost {
+		d, err = Dicts.AddWords(r.PathValue("name"), req.Words...)
+	} else {
+		d, err = Dicts.RemoveWords(r.PathValue("name"), req.Words...)
+	}
+	if err != nil {
+		writeDictError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+func dictStoreEnabled(w http.ResponseWriter) bool {
+	if Dicts == nil {
+		http.Error(w, "named dictionaries are disabled (start the server with -dict-store)", http.StatusNotImplemented)
+		return false
+	}
+	return true
+}
+
+func writeDictError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrDictNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrDictName), errors.Is(err, errInvalidDict):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	out, _ := util.MarshalNoEscape(v, true)
+	w.Write(out)
+}
@@ -0,0 +1,118 @@
+package kospell
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/dictmatch"
+)
+
+// koreanSuffixes lists common 조사 and 어미 (mostly copula forms) that attach
+// to a protected noun without a space: "목제솜틀기는", "목제솜틀기예요".
+// Chains such as "에서" + "는" are matched by repeating the lookup.
+var koreanSuffixes = sortedByLenDesc([]string{
+	// 격조사
+	"이", "가", "께서", "에서", "을", "를", "의", "에", "에게", "께", "한테", "더러",
+	"로", "으로", "로서", "으로서", "로써", "으로써", "로부터", "으로부터", "와", "과",
+	"이랑", "랑", "하고", "보다", "처럼", "같이", "만큼", "아", "야", "이여", "여",
+	// 보조사
+	"은", "는", "도", "만", "까지", "부터", "조차", "마저", "밖에", "뿐", "마다",
+	"이나", "나", "이나마", "나마", "이든", "든", "이라도", "라도", "이야", "요",
+	// 서술격 조사 '이다' 활용
+	"이다", "다", "입니다", "이에요", "예요", "였다", "이었다", "이었고", "였고",
+	"이고", "고", "이며", "며", "이면", "면", "이라서", "라서", "이라", "라", "이라고", "라고",
+	"인", "일", "이니까", "니까", "이지만", "지만", "이죠", "죠",
+})
+
+func sortedByLenDesc(ss []string) []string {
+	sort.SliceStable(ss, func(i, j int) bool { return len(ss[i]) > len(ss[j]) })
+	return ss
+}
+
+// maxSuffixChain bounds how many 조사/어미 may be stacked ("에서"+"는"+"요").
+const maxSuffixChain = 3
+
+// suffixChainLen returns the byte length of the longest run of 조사/어미 at
+// the start of s that ends at a word boundary, or 0.
+func suffixChainLen(s string) int {
+	return suffixChainLenDepth(s, maxSuffixChain)
+}
+
+func suffixChainLenDepth(s string, depth int) int {
+	if depth == 0 {
+		return 0
+	}
+	for _, suf := range koreanSuffixes { // longest first
+		if !strings.HasPrefix(s, suf) {
+			continue
+		}
+		rest := s[len(suf):]
+		if atWordBoundary(rest) {
+			return len(suf)
+		}
+		if n := suffixChainLenDepth(rest, depth-1); n > 0 {
+			return len(suf) + n
+		}
+	}
+	return 0
+}
+
+// atWordBoundary reports whether s starts at the end of a word.
+func atWordBoundary(s string) bool {
+	if s == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// rejoinDetachedSuffixes undoes suggestions that split a 조사/어미 off a
+// protected word: if origin has "목제솜틀기는" and fixed has "목제솜틀기 는",
+// the space is removed. A suffix that was already separated in origin
+// ("목제솜틀기 를") is a genuine spacing error and stays as suggested.
+func rejoinDetachedSuffixes(origin, fixed string, m *dictmatch.Matcher, only map[int]struct{}) string {
+	matches := m.FindAll(fixed, only)
+	if len(matches) == 0 {
+		return fixed
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, mt := range matches {
+		ws := mt.End
+		for ws < len(fixed) {
+			r, size := utf8.DecodeRuneInString(fixed[ws:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			ws += size
+		}
+		if ws == mt.End {
+			continue
+		}
+		n := suffixChainLen(fixed[ws:])
+		if n == 0 || !attachedIn(origin, m, mt.Word, fixed[ws:ws+n]) {
+			continue
+		}
+		b.WriteString(fixed[last:mt.End])
+		last = ws
+	}
+	if last == 0 {
+		return fixed
+	}
+	b.WriteString(fixed[last:])
+	return b.String()
+}
+
+// attachedIn reports whether s contains the word with id (in any inner
+// spacing) immediately followed by suffix.
+func attachedIn(s string, m *dictmatch.Matcher, id int, suffix string) bool {
+	for _, mt := range m.FindAll(s, map[int]struct{}{id: {}}) {
+		if strings.HasPrefix(s[mt.End:], suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package kospell
+
+import (
+	"testing"
+
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func TestSuffixChainLen(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"는 좋다", "는"},
+		{"를", "를"},
+		{"에서는 팔아요", "에서는"},
+		{"이에요.", "이에요"},
+		{"덕분에", ""},
+		{"공장", ""},
+	}
+	for _, tt := range tests {
+		if got := suffixChainLen(tt.in); tt.in[:got] != tt.want {
+			t.Errorf("suffixChainLen(%q) matched %q, want %q", tt.in, tt.in[:got], tt.want)
+		}
+	}
+}
+
+func TestKeepCorrectionForDict_Particles(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		suggest string
+		keep    bool
+		want    string
+	}{
+		{"detached particle is a real spacing fix", "목제솜틀기 를", "목제솜틀기를", true, "목제솜틀기를"},
+		{"stem split only", "목제솜틀기는", "목제 솜틀기는", false, ""},
+		{"particle split off", "목제솜틀기는", "목제솜틀기 는", false, ""},
+		{"stem split and particle split", "목제솜틀기에서는", "목제 솜틀기 에서는", false, ""},
+		{"stem split plus real spacing", "목제솜틀기덕분에", "목제 솜틀기 덕분에", true, "목제솜틀기 덕분에"},
+		{"wrong particle fixed", "목제솜틀기 은", "목제솜틀기는", true, "목제솜틀기는"},
+		{"particle split kept but other word fixed", "목제솜틀기는 좋와요", "목제솜틀기 는 좋아요", true, "목제솜틀기는 좋아요"},
+	}
+	m := dictMatcher(NewDict("목제솜틀기"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &model.Correction{
+				Origin:    tt.origin,
+				Suggest:   []string{tt.suggest},
+				Distances: []int{0},
+			}
+			if got := keepCorrectionForDict(item, m); got != tt.keep {
+				t.Fatalf("keepCorrectionForDict() = %v, want %v", got, tt.keep)
+			}
+			if tt.keep && item.Suggest[0] != tt.want {
+				t.Fatalf("suggestion = %q, want %q", item.Suggest[0], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package kospell
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+// Pattern protects every span of text it matches: either a literal Word
+// (optionally case-insensitive, so "kafka" also covers "Kafka" and "KAFKA")
+// or a Go regular expression (ticket IDs, versions, hashtags, URLs, …).
+// Any correction whose origin overlaps a match is suppressed.
+type Pattern struct {
+	Word       string `json:"word,omitempty"`
+	Regex      string `json:"regex,omitempty"`
+	IgnoreCase bool   `json:"ignore_case,omitempty"`
+}
+
+func (p Pattern) compile() (*regexp.Regexp, error) {
+	expr := p.Regex
+	if p.Word != "" {
+		expr = regexp.QuoteMeta(p.Word)
+	}
+	if p.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+func (p Pattern) validate() error {
+	switch {
+	case p.Word != "" && p.Regex != "":
+		return fmt.Errorf("set either \"word\" or \"regex\", not both")
+	case strings.TrimSpace(p.Word) == "" && p.Regex == "":
+		return fmt.Errorf("empty \"word\" and \"regex\"")
+	}
+	re, err := p.compile()
+	if err != nil {
+		return err
+	}
+	if re.MatchString("") {
+		return fmt.Errorf("regex %q matches the empty string", p.Regex)
+	}
+	return nil
+}
+
+// dictPatterns compiles dict.Patterns once. Entries that fail to compile are
+// skipped; LoadDict and the server reject them earlier through Validate.
+func dictPatterns(dict *Dict) []*regexp.Regexp {
+	if dict == nil {
+		return nil
+	}
+	dict.patternsOnce.Do(func() {
+		for _, p := range dict.Patterns {
+			if p.validate() != nil {
+				continue
+			}
+			re, _ := p.compile()
+			dict.patterns = append(dict.patterns, re)
+		}
+	})
+	return dict.patterns
+}
+
+// suppressDictPatterns drops every backend correction whose origin overlaps
+// a dict pattern match in its chunk. The dict's own replace/banned items
+// (error_type style) are kept: patterns protect against the backend, not
+// against the user's rules. It reports whether anything was removed.
+func suppressDictPatterns(res *model.Result, dict *Dict) bool {
+	patterns := dictPatterns(dict)
+	if res == nil || len(patterns) == 0 {
+		return false
+	}
+
+	removed := 0
+	newCorrs := res.Corrections[:0]
+	for _, c := range res.Corrections {
+		spans := patternSpans(c.Input, patterns)
+		if len(spans) > 0 {
+			kept := c.Items[:0]
+			for _, item := range c.Items {
+				if item.ErrorType != errorTypeStyle && overlapsPattern(c.Input, item, spans) {
+					removed++
+					continue
+				}
+				kept = append(kept, item)
+			}
+			c.Items = kept
+		}
+		if len(c.Items) > 0 {
+			newCorrs = append(newCorrs, c)
+		}
+	}
+	res.Corrections = newCorrs
+	res.ErrorCount -= removed
+	return removed > 0
+}
+
+// patternSpans returns the rune ranges of all pattern matches in input.
+func patternSpans(input string, patterns []*regexp.Regexp) [][2]int {
+	var spans [][2]int
+	for _, re := range patterns {
+		for _, loc := range re.FindAllStringIndex(input, -1) {
+			start := utf8.RuneCountInString(input[:loc[0]])
+			spans = append(spans, [2]int{start, start + utf8.RuneCountInString(input[loc[0]:loc[1]])})
+		}
+	}
+	return spans
+}
+
+// overlapsPattern reports whether item overlaps any span. The item's own
+// offsets are used when they point at its origin; otherwise (LLM offsets are
+// often off) every occurrence of the origin in input is considered.
+func overlapsPattern(input string, item model.Correction, spans [][2]int) bool {
+	runes := []rune(input)
+	if item.Start >= 0 && item.Start < item.End && item.End <= len(runes) &&
+		string(runes[item.Start:item.End]) == item.Origin {
+		return overlapsSpans(item.Start, item.End, spans)
+	}
+	if item.Origin == "" {
+		return false
+	}
+	n := utf8.RuneCountInString(item.Origin)
+	for off := 0; ; {
+		i := strings.Index(input[off:], item.Origin)
+		if i < 0 {
+			return false
+		}
+		start := utf8.RuneCountInString(input[:off+i])
+		if overlapsSpans(start, start+n, spans) {
+			return true
+		}
+		off += i + len(item.Origin)
+	}
+}
+
+func overlapsSpans(start, end int, spans [][2]int) bool {
+	for _, s := range spans {
+		if start < s[1] && s[0] < end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package kospell
+
+import (
+	"testing"
+
+	"github.com/Alfex4936/kospell/internal/chunk"
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func TestDictValidate_Patterns(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Pattern
+		ok   bool
+	}{
+		{"word", Pattern{Word: "kafka", IgnoreCase: true}, true},
+		{"regex", Pattern{Regex: `[A-Z]+-\d+`}, true},
+		{"both", Pattern{Word: "kafka", Regex: "kafka"}, false},
+		{"neither", Pattern{}, false},
+		{"bad regex", Pattern{Regex: "(["}, false},
+		{"empty match", Pattern{Regex: `\d*`}, false},
+	}
+	for _, tt := range tests {
+		err := (&Dict{Patterns: []Pattern{tt.p}}).Validate()
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestSuppressDictPatterns(t *testing.T) {
+	input := "KAFKA 설정은 ABC-1234 티켓 참고 하세요."
+	res := &model.Result{
+		Original:   input,
+		ErrorCount: 4,
+		Corrections: []model.Chunk{{
+			Idx:   0,
+			Input: input,
+			Items: []model.Correction{
+				{Start: 0, End: 9, Origin: "KAFKA 설정은", Suggest: []string{"카프카 설정은"}},
+				{Start: 14, End: 21, Origin: "1234 티켓", Suggest: []string{"1234티켓"}},
+				{Start: 22, End: 28, Origin: "참고 하세요", Suggest: []string{"참고하세요"}},
+				// LLM-style wrong offsets: located by origin instead.
+				{Start: 0, End: 3, Origin: "ABC", Suggest: []string{"ABC "}},
+			},
+		}},
+	}
+	dict := &Dict{Patterns: []Pattern{
+		{Word: "kafka", IgnoreCase: true},
+		{Regex: `[A-Z]+-\d+`},
+	}}
+
+	if !suppressDictPatterns(res, dict) {
+		t.Fatal("suppressDictPatterns() = false, want true")
+	}
+	if res.ErrorCount != 1 || len(res.Corrections) != 1 || len(res.Corrections[0].Items) != 1 {
+		t.Fatalf("got %d errors, corrections %+v", res.ErrorCount, res.Corrections)
+	}
+	if got := res.Corrections[0].Items[0].Origin; got != "참고 하세요" {
+		t.Fatalf("kept %q, want %q", got, "참고 하세요")
+	}
+}
+
+func TestSuppressDictPatterns_KeepsDictRules(t *testing.T) {
+	original := "Github 저장소를 github에 올렸다"
+	res := &model.Result{
+		Original:   original,
+		ErrorCount: 1,
+		Corrections: []model.Chunk{{Idx: 0, Input: original, Items: []model.Correction{
+			{Start: 12, End: 18, Origin: "github", Suggest: []string{"깃허브"}},
+		}}},
+	}
+	dict := &Dict{
+		Replace:  []Replacement{{From: "Github", To: "GitHub"}},
+		Patterns: []Pattern{{Word: "github", IgnoreCase: true}},
+	}
+
+	applyDictRules(res, []chunk.Piece{{Text: original}}, dict)
+	suppressDictPatterns(res, dict)
+
+	if res.ErrorCount != 1 || len(res.Corrections) != 1 || len(res.Corrections[0].Items) != 1 {
+		t.Fatalf("got %d errors, corrections %+v", res.ErrorCount, res.Corrections)
+	}
+	if item := res.Corrections[0].Items[0]; item.Origin != "Github" || item.Suggest[0] != "GitHub" || item.ErrorType != errorTypeStyle {
+		t.Fatalf("kept %+v, want the Github→GitHub replacement", item)
+	}
+}
+
+func TestMergeDicts_Patterns(t *testing.T) {
+	d := MergeDicts(NewDict("a"), &Dict{Patterns: []Pattern{{Regex: "x+"}}})
+	if d.isEmpty() || len(d.Patterns) != 1 {
+		t.Fatalf("MergeDicts() = %+v", d)
+	}
+	if (&Dict{Patterns: []Pattern{{Regex: "x+"}}}).isEmpty() {
+		t.Fatal("dict with only patterns reported empty")
+	}
+}
@@ -0,0 +1,192 @@
+package kospell
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/chunk"
+	"github.com/Alfex4936/kospell/internal/model"
+	"github.com/Alfex4936/kospell/internal/util"
+)
+
+// dictRule is one replace/banned entry ready for matching.
+type dictRule struct {
+	match    string
+	skipIf   string // text already in canonical form at the match position
+	suggest  []string
+	help     string
+	severity string
+}
+
+func compileDictRules(dict *Dict) []dictRule {
+	if dict == nil {
+		return nil
+	}
+	rules := make([]dictRule, 0, len(dict.Replace)+len(dict.Banned))
+	for _, r := range dict.Replace {
+		if r.From == "" || r.From == r.To {
+			continue
+		}
+		help := r.Message
+		if help == "" {
+			help = fmt.Sprintf("사용자 사전: '%s' 대신 '%s'을(를) 사용합니다.", r.From, r.To)
+		}
+		rules = append(rules, dictRule{
+			match:    r.From,
+			skipIf:   r.To,
+			suggest:  []string{r.To},
+			help:     help,
+			severity: orDefault(r.Severity, SeverityError),
+		})
+	}
+	for _, b := range dict.Banned {
+		if b.Word == "" {
+			continue
+		}
+		help := b.Message
+		if help == "" {
+			help = fmt.Sprintf("사용자 사전: '%s'은(는) 사용하지 않는 표현입니다.", b.Word)
+		}
+		rules = append(rules, dictRule{
+			match:    b.Word,
+			suggest:  b.Suggest,
+			help:     help,
+			severity: orDefault(b.Severity, SeverityWarning),
+		})
+	}
+	return rules
+}
+
+// findDictRuleCorrections returns the style corrections for input, with rune
+// offsets relative to input. Overlapping matches resolve leftmost-longest.
+func findDictRuleCorrections(input string, rules []dictRule) []model.Correction {
+	type hit struct {
+		start, end int // byte offsets
+		rule       *dictRule
+	}
+
+	var hits []hit
+	for i := range rules {
+		r := &rules[i]
+		for from := 0; from < len(input); {
+			pos := strings.Index(input[from:], r.match)
+			if pos < 0 {
+				break
+			}
+			pos += from
+			if r.skipIf == "" || !strings.HasPrefix(input[pos:], r.skipIf) {
+				hits = append(hits, hit{start: pos, end: pos + len(r.match), rule: r})
+			}
+			from = pos + len(r.match)
+		}
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].start != hits[j].start {
+			return hits[i].start < hits[j].start
+		}
+		return hits[i].end > hits[j].end
+	})
+
+	out := make([]model.Correction, 0, len(hits))
+	lastEnd := 0
+	for _, h := range hits {
+		if h.start < lastEnd {
+			continue
+		}
+		lastEnd = h.end
+
+		suggest := append([]string(nil), h.rule.suggest...)
+		dists := make([]int, len(suggest))
+		for i, s := range suggest {
+			dists[i] = util.Levenshtein(h.rule.match, s)
+		}
+		start := utf8.RuneCountInString(input[:h.start])
+		out = append(out, model.Correction{
+			Start:     start,
+			End:       start + utf8.RuneCountInString(h.rule.match),
+			Origin:    h.rule.match,
+			Suggest:   suggest,
+			Distances: dists,
+			Help:      h.rule.help,
+			ErrorType: errorTypeStyle,
+			Severity:  h.rule.severity,
+		})
+	}
+	return out
+}
+
+// applyDictRules adds the dictionary's replace/banned matches to res.
+// pieces are res.Original's chunks in order (chunk i has Idx i). A dictionary
+// match replaces any backend correction it overlaps: the user's rule wins.
+// It reports whether any correction was added.
+func applyDictRules(res *model.Result, pieces []chunk.Piece, dict *Dict) bool {
+	rules := compileDictRules(dict)
+	if res == nil || len(rules) == 0 {
+		return false
+	}
+
+	byIdx := make(map[int]int, len(res.Corrections)) // Idx → position in res.Corrections
+	for i, ch := range res.Corrections {
+		byIdx[ch.Idx] = i
+	}
+
+	starts := chunk.RuneStarts(res.Original, pieces)
+	added := false
+	for idx, p := range pieces {
+		part := p.Own()
+		found := findDictRuleCorrections(part, rules)
+		if len(found) == 0 {
+			continue
+		}
+		added = true
+
+		pos, ok := byIdx[idx]
+		if !ok {
+			res.Corrections = append(res.Corrections, model.Chunk{Idx: idx, Input: part, Offset: starts[idx]})
+			pos = len(res.Corrections) - 1
+			byIdx[idx] = pos
+		}
+
+		ch := &res.Corrections[pos]
+		kept := ch.Items[:0]
+		for _, item := range ch.Items {
+			if !overlapsAny(item, found) {
+				kept = append(kept, item)
+			}
+		}
+		ch.Items = append(kept, found...)
+		sort.SliceStable(ch.Items, func(i, j int) bool { return ch.Items[i].Start < ch.Items[j].Start })
+	}
+	if !added {
+		return false
+	}
+
+	sort.SliceStable(res.Corrections, func(i, j int) bool { return res.Corrections[i].Idx < res.Corrections[j].Idx })
+	res.ErrorCount = 0
+	for _, ch := range res.Corrections {
+		res.ErrorCount += len(ch.Items)
+	}
+	return true
+}
+
+func overlapsAny(item model.Correction, others []model.Correction) bool {
+	for _, o := range others {
+		if item.Start < o.End && o.Start < item.End {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
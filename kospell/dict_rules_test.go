@@ -0,0 +1,123 @@
+package kospell
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Alfex4936/kospell/internal/chunk"
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func TestLoadDict_BackwardCompatibleAndRich(t *testing.T) {
+	dir := t.TempDir()
+
+	legacy := filepath.Join(dir, "legacy.json")
+	if err := os.WriteFile(legacy, []byte(`{"words": ["목제솜틀기"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := LoadDict(legacy)
+	if err != nil || len(d.Words) != 1 || len(d.Replace) != 0 {
+		t.Fatalf("LoadDict(legacy) = %+v, %v", d, err)
+	}
+
+	rich := filepath.Join(dir, "rich.json")
+	data := `{"words": ["kafka"], "replace": [{"from": "됬", "to": "됐"}], "banned": [{"word": "노가다", "suggest": ["막일"]}]}`
+	if err := os.WriteFile(rich, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err = LoadDict(rich)
+	if err != nil || len(d.Replace) != 1 || len(d.Banned) != 1 {
+		t.Fatalf("LoadDict(rich) = %+v, %v", d, err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"banned": [{"word": "x", "severity": "fatal"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDict(bad); err == nil {
+		t.Fatal("LoadDict should reject an invalid severity")
+	}
+}
+
+func TestApplyDictRules_AddsStyleCorrections(t *testing.T) {
+	original := "일이 잘됬다. 노가다 끝."
+	res := &model.Result{Original: original}
+	dict := &Dict{
+		Replace: []Replacement{{From: "됬", To: "됐"}},
+		Banned:  []BannedWord{{Word: "노가다", Suggest: []string{"막일"}}},
+	}
+
+	if !applyDictRules(res, []chunk.Piece{{Text: original}}, dict) {
+		t.Fatal("applyDictRules reported no additions")
+	}
+	if res.ErrorCount != 2 || len(res.Corrections) != 1 {
+		t.Fatalf("ErrorCount/chunks = %d/%d, want 2/1", res.ErrorCount, len(res.Corrections))
+	}
+
+	items := res.Corrections[0].Items
+	r := []rune(original)
+	for _, it := range items {
+		if got := string(r[it.Start:it.End]); got != it.Origin {
+			t.Fatalf("span %q != origin %q", got, it.Origin)
+		}
+		if it.ErrorType != errorTypeStyle {
+			t.Fatalf("ErrorType = %q, want style", it.ErrorType)
+		}
+	}
+	if items[0].Severity != SeverityError || items[1].Severity != SeverityWarning {
+		t.Fatalf("severities = %q/%q", items[0].Severity, items[1].Severity)
+	}
+
+	// style items survive the default error type filter and are applied.
+	filterResultByErrorTypes(res, defaultAllowedErrorTypes(), dict)
+	if got, want := res.Corrected, "일이 잘됐다. 막일 끝."; got != want {
+		t.Fatalf("Corrected = %q, want %q", got, want)
+	}
+}
+
+func TestFilterByErrorTypes_StyleIsAType(t *testing.T) {
+	original := "일이 잘됬다. 안녕 하세요"
+	dict := &Dict{Replace: []Replacement{{From: "됬", To: "됐"}}}
+	check := func(types ...string) *model.Result {
+		res := &model.Result{Original: original, Corrections: []model.Chunk{{Input: original, Items: []model.Correction{
+			{Start: 8, End: 14, Origin: "안녕 하세요", Suggest: []string{"안녕하세요"}, Help: "띄어쓰기"},
+		}}}}
+		applyDictRules(res, []chunk.Piece{{Text: original}}, dict)
+		if err := FilterByErrorTypes(res, types, dict); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := check("spacing"); res.ErrorCount != 1 || res.Corrections[0].Items[0].ErrorType != errorTypeSpacing {
+		t.Fatalf("error_types [spacing] kept %+v, want only the spacing item", res.Corrections)
+	}
+	if res := check("사용자사전"); res.ErrorCount != 1 || res.Corrections[0].Items[0].ErrorType != errorTypeStyle {
+		t.Fatalf("error_types [사용자사전] kept %+v, want only the style item", res.Corrections)
+	}
+	if res := check(); res.ErrorCount != 2 {
+		t.Fatalf("default error types kept %d items, want 2", res.ErrorCount)
+	}
+}
+
+func TestApplyDictRules_OverridesOverlappingBackendItem(t *testing.T) {
+	original := "카프카를 쓴다"
+	res := &model.Result{
+		Original:   original,
+		ErrorCount: 1,
+		Corrections: []model.Chunk{{Idx: 0, Input: original, Items: []model.Correction{
+			{Start: 0, End: 4, Origin: "카프카를", Suggest: []string{"카프 카를"}, Help: "띄어쓰기 오류"},
+		}}},
+	}
+	dict := &Dict{Replace: []Replacement{{From: "카프카", To: "Kafka"}}}
+
+	applyDictRules(res, []chunk.Piece{{Text: original}}, dict)
+
+	items := res.Corrections[0].Items
+	if len(items) != 1 || items[0].Origin != "카프카" || items[0].Suggest[0] != "Kafka" {
+		b, _ := json.Marshal(items)
+		t.Fatalf("items = %s", b)
+	}
+}
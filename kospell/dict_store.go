@@ -0,0 +1,297 @@
+package kospell
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DictStorePollInterval is how often DictStore.Watch rescans its directory.
+const DictStorePollInterval = 2 * time.Second
+
+var (
+	// ErrDictNotFound is returned for names missing from a DictStore.
+	ErrDictNotFound = errors.New("kospell: dictionary not found")
+	// ErrDictName is returned for names that are not [A-Za-z0-9_-]{1,64}.
+	ErrDictName = errors.New("kospell: invalid dictionary name")
+
+	reDictName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+)
+
+// DictStore keeps named dictionaries as <dir>/<name>.json files.
+//
+// Dicts returned by Get are shared snapshots and must not be modified;
+// every write stores a fresh Dict. Files edited on disk are picked up by
+// Reload (or Watch).
+type DictStore struct {
+	dir string
+
+	mu    sync.RWMutex
+	dicts map[string]storedDict
+}
+
+type storedDict struct {
+	dict    *Dict
+	modTime time.Time
+	size    int64
+}
+
+// OpenDictStore opens (creating if needed) the store in dir and loads
+// every dictionary in it.
+func OpenDictStore(dir string) (*DictStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &DictStore{dir: dir, dicts: map[string]storedDict{}}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Dir returns the directory backing the store.
+func (s *DictStore) Dir() string { return s.dir }
+
+// Names returns the stored dictionary names, sorted.
+func (s *DictStore) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.dicts))
+	for n := range s.dicts {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the named dictionary.
+func (s *DictStore) Get(name string) (*Dict, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.dicts[name]
+	return e.dict, ok
+}
+
+// Lookup merges the named dictionaries into one Dict.
+func (s *DictStore) Lookup(names ...string) (*Dict, error) {
+	dicts, err := s.entries(names...)
+	if err != nil {
+		return nil, err
+	}
+	return MergeDicts(dicts...), nil
+}
+
+// entries returns the current snapshots of the named dictionaries, in order.
+func (s *DictStore) entries(names ...string) ([]*Dict, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	dicts := make([]*Dict, 0, len(names))
+	for _, n := range names {
+		e, ok := s.dicts[n]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrDictNotFound, n)
+		}
+		dicts = append(dicts, e.dict)
+	}
+	return dicts, nil
+}
+
+// Put creates or replaces the named dictionary.
+func (s *DictStore) Put(name string, d *Dict) error {
+	if !reDictName.MatchString(name) {
+		return ErrDictName
+	}
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writeLocked(name, MergeDicts(d))
+}
+
+// Delete removes the named dictionary.
+func (s *DictStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dicts[name]; !ok {
+		return ErrDictNotFound
+	}
+	if err := os.Remove(s.path(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	delete(s.dicts, name)
+	return nil
+}
+
+// AddWords adds protected words to the named dictionary, creating it if
+// needed, and returns the updated Dict.
+func (s *DictStore) AddWords(name string, words ...string) (*Dict, error) {
+	if !reDictName.MatchString(name) {
+		return nil, ErrDictName
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := MergeDicts(s.dicts[name].dict)
+	have := make(map[string]struct{}, len(next.Words))
+	for _, w := range next.Words {
+		have[w] = struct{}{}
+	}
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if _, ok := have[w]; ok || w == "" {
+			continue
+		}
+		have[w] = struct{}{}
+		next.Words = append(next.Words, w)
+	}
+	if err := s.writeLocked(name, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// RemoveWords removes protected words from the named dictionary and returns
+// the updated Dict.
+func (s *DictStore) RemoveWords(name string, words ...string) (*Dict, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, ok := s.dicts[name]
+	if !ok {
+		return nil, ErrDictNotFound
+	}
+	drop := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		drop[strings.TrimSpace(w)] = struct{}{}
+	}
+	next := MergeDicts(cur.dict)
+	next.Words = next.Words[:0]
+	for _, w := range cur.dict.Words {
+		if _, ok := drop[w]; !ok {
+			next.Words = append(next.Words, w)
+		}
+	}
+	if err := s.writeLocked(name, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// Reload rescans the directory: new or changed files are (re)loaded and
+// deleted files are dropped. A file that fails to load keeps its last good
+// version; the errors are returned joined.
+func (s *DictStore) Reload() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	seen := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok || !reDictName.MatchString(name) {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		info, err := e.Info()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		cur, ok := s.dicts[name]
+		if ok && cur.modTime.Equal(info.ModTime()) && cur.size == info.Size() {
+			continue
+		}
+		d, err := LoadDict(s.path(name))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.dicts[name] = storedDict{dict: d, modTime: info.ModTime(), size: info.Size()}
+	}
+	for name := range s.dicts {
+		if _, ok := seen[name]; !ok {
+			delete(s.dicts, name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Watch calls Reload every interval until ctx is done, logging failures.
+func (s *DictStore) Watch(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := s.Reload(); err != nil {
+				log.Printf("kospell: dictionary store reload: %v", err)
+			}
+		}
+	}
+}
+
+func (s *DictStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+func (s *DictStore) writeLocked(name string, d *Dict) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	p := s.path(name)
+	if err := writeFileAtomic(p, data); err != nil {
+		return err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	s.dicts[name] = storedDict{dict: d, modTime: info.ModTime(), size: info.Size()}
+	return nil
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it
+// into place, so readers never observe a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp) // no-op after a successful rename
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
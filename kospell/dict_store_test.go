@@ -0,0 +1,198 @@
+package kospell
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Alfex4936/kospell/internal/dictmatch"
+	internalllm "github.com/Alfex4936/kospell/internal/llm"
+)
+
+func TestDictStore_CRUDAndReload(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenDictStore(dir)
+	if err != nil {
+		t.Fatalf("OpenDictStore: %v", err)
+	}
+
+	if err := s.Put("../evil", NewDict("x")); !errors.Is(err, ErrDictName) {
+		t.Fatalf("Put(../evil) err = %v, want ErrDictName", err)
+	}
+	if err := s.Put("brand", NewDict("목제솜틀기")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := s.AddWords("brand", "kafka", "kafka"); err != nil {
+		t.Fatalf("AddWords: %v", err)
+	}
+	d, err := s.RemoveWords("brand", "목제솜틀기")
+	if err != nil || len(d.Words) != 1 || d.Words[0] != "kafka" {
+		t.Fatalf("RemoveWords = %+v, %v", d, err)
+	}
+
+	// reopen: state persisted on disk
+	s2, err := OpenDictStore(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, ok := s2.Get("brand"); !ok || len(got.Words) != 1 {
+		t.Fatalf("reopened brand = %+v, %v", got, ok)
+	}
+
+	// external edit is picked up; a broken file keeps the last good version
+	p := filepath.Join(dir, "brand.json")
+	future := time.Now().Add(time.Minute)
+	if err := os.WriteFile(p, []byte(`{"words": ["a", "b"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(p, future, future)
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got, _ := s.Get("brand"); len(got.Words) != 2 {
+		t.Fatalf("after edit Words = %v, want 2", got.Words)
+	}
+	if err := os.WriteFile(p, []byte(`{not json`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(p, future.Add(time.Minute), future.Add(time.Minute))
+	if err := s.Reload(); err == nil {
+		t.Fatal("Reload should report the broken file")
+	}
+	if got, _ := s.Get("brand"); len(got.Words) != 2 {
+		t.Fatalf("broken file dropped last good dict: %v", got.Words)
+	}
+
+	if _, err := s.Lookup("brand", "missing"); !errors.Is(err, ErrDictNotFound) {
+		t.Fatalf("Lookup(missing) err = %v", err)
+	}
+	if err := s.Delete("brand"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := s.Get("brand"); ok {
+		t.Fatal("brand still present after Delete")
+	}
+}
+
+func TestDictHandlers(t *testing.T) {
+	store, err := OpenDictStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := Dicts
+	Dicts = store
+	t.Cleanup(func() { Dicts = prev })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/dicts", DictsHandler)
+	mux.HandleFunc("/v1/dicts/{name}", DictHandler)
+	mux.HandleFunc("/v1/dicts/{name}/words", DictWordsHandler)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := do(http.MethodPut, "/v1/dicts/tech", `{"words": ["kafka"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(http.MethodPost, "/v1/dicts/tech/words", `{"words": ["FastAPI"]}`); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "FastAPI") {
+		t.Fatalf("POST words = %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(http.MethodGet, "/v1/dicts", ""); !strings.Contains(rec.Body.String(), `"tech"`) {
+		t.Fatalf("GET list = %s", rec.Body)
+	}
+	if rec := do(http.MethodPut, "/v1/dicts/bad", `{"banned": [{"word": ""}]}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("PUT invalid = %d, want 400", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/v1/dicts/tech", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE = %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/v1/dicts/tech", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET deleted = %d, want 404", rec.Code)
+	}
+}
+
+func TestCheckSpellHandler_RejectsDictPath(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/check-spell", strings.NewReader(`{"text": "x", "backend": "nara", "dict_path": "/etc/passwd"}`))
+	CheckSpellHandler(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+// BenchmarkCheckSpellHandler_SharedDict checks with a 10 000-word server
+// dictionary, alone and with a named one. The merged dictionary is shared
+// across requests, so its matcher is built by the first request only.
+func BenchmarkCheckSpellHandler_SharedDict(b *testing.B) {
+	var calls atomic.Int32
+	srv := fakeChatServer(b, &calls)
+	defer srv.Close()
+
+	words := make([]string, 10000)
+	for i := range words {
+		words[i] = fmt.Sprintf("용어%05d", i)
+	}
+	path := filepath.Join(b.TempDir(), "default.json")
+	data, _ := json.Marshal(NewDict(words...))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		b.Fatal(err)
+	}
+	watcher, err := WatchDicts(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	store, err := OpenDictStore(b.TempDir())
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := store.Put("brand", NewDict("Kafka")); err != nil {
+		b.Fatal(err)
+	}
+
+	savedDefault, savedStore, savedLLM, savedBuild := DefaultDicts, Dicts, LLMChecker, newDictMatcher
+	defer func() {
+		DefaultDicts, Dicts, LLMChecker, newDictMatcher = savedDefault, savedStore, savedLLM, savedBuild
+	}()
+	DefaultDicts, Dicts, LLMChecker = watcher, store, internalllm.New("key", "", srv.URL)
+	var builds atomic.Int64
+	newDictMatcher = func(raws []string) *dictmatch.Matcher {
+		builds.Add(1)
+		return dictmatch.New(raws)
+	}
+
+	for _, bc := range []struct{ name, body string }{
+		{"default", `{"text": "일이 잘 됬다. 용어00042를 썼다.", "backend": "openai"}`},
+		{"named", `{"text": "일이 잘 됬다. Kafka를 썼다.", "backend": "openai", "dicts": ["brand"]}`},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			check := func() {
+				rec := httptest.NewRecorder()
+				CheckSpellHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell", strings.NewReader(bc.body)))
+				if rec.Code != http.StatusOK {
+					b.Fatalf("status = %d: %s", rec.Code, rec.Body)
+				}
+			}
+			check() // the first request compiles the dictionary
+			builds.Store(0)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				check()
+			}
+			b.ReportMetric(float64(builds.Load())/float64(b.N), "builds/op")
+			if n := builds.Load(); n != 0 {
+				b.Fatalf("matcher built %d times in %d requests, want once per dictionary", n, b.N)
+			}
+		})
+	}
+}
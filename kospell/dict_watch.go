@@ -0,0 +1,121 @@
+package kospell
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DictWatchInterval is the default polling interval for DictWatcher.Run.
+const DictWatchInterval = 2 * time.Second
+
+// DictWatcher keeps the merged contents of one or more dictionary files
+// current. Files are polled by modification time and size; a change reloads
+// all files and swaps the merged Dict atomically, so callers holding a
+// snapshot from Dict never see a half-updated dictionary. A file that fails
+// to load leaves the last good Dict in place and is not retried until it
+// changes again.
+type DictWatcher struct {
+	paths []string
+
+	cur atomic.Pointer[Dict]
+
+	mu     sync.Mutex  // serializes Reload
+	stamps []fileStamp // of the last successful load
+	failed []fileStamp // of the last failed load, nil after a success
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// WatchDicts loads paths with LoadDict and returns a watcher over them.
+// The initial load must succeed.
+func WatchDicts(paths ...string) (*DictWatcher, error) {
+	w := &DictWatcher{paths: paths, stamps: make([]fileStamp, len(paths))}
+	if _, err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Dict returns the current snapshot. It must not be modified.
+func (w *DictWatcher) Dict() *Dict {
+	if w == nil {
+		return nil
+	}
+	return w.cur.Load()
+}
+
+// Paths returns the watched files.
+func (w *DictWatcher) Paths() []string { return w.paths }
+
+// Reload reloads the files if any of them changed since the last successful
+// load, and reports whether a new snapshot was installed. Once a load fails,
+// the same files are not loaded, nor the error returned, again until one of
+// them changes.
+func (w *DictWatcher) Reload() (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stamps := make([]fileStamp, len(w.paths))
+	for i, p := range w.paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return false, err
+		}
+		stamps[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	if w.cur.Load() != nil && sameStamps(stamps, w.stamps) {
+		return false, nil
+	}
+	if w.failed != nil && sameStamps(stamps, w.failed) {
+		return false, nil // already reported; wait for the next edit
+	}
+
+	dicts := make([]*Dict, 0, len(w.paths))
+	for _, p := range w.paths {
+		d, err := LoadDict(p)
+		if err != nil {
+			w.failed = stamps
+			return false, err
+		}
+		dicts = append(dicts, d)
+	}
+	w.cur.Store(MergeDicts(dicts...))
+	w.stamps, w.failed = stamps, nil
+	return true, nil
+}
+
+func sameStamps(a, b []fileStamp) bool {
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
+
+// Run polls for changes every interval until ctx is done, logging reloads
+// and failures.
+func (w *DictWatcher) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			ok, err := w.Reload()
+			if err != nil {
+				log.Printf("kospell: dictionary reload failed, keeping last good version: %v", err)
+			} else if ok {
+				log.Printf("kospell: dictionary reloaded (%d word(s))", len(w.Dict().Words))
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package kospell
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDictWatcher_ReloadSwapsAndKeepsLastGood(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	write := func(p, data string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	write(a, `{"words": ["kafka"]}`, now)
+	write(b, `{"replace": [{"from": "됬", "to": "됐"}]}`, now)
+
+	w, err := WatchDicts(a, b)
+	if err != nil {
+		t.Fatalf("WatchDicts: %v", err)
+	}
+	first := w.Dict()
+	if len(first.Words) != 1 || len(first.Replace) != 1 {
+		t.Fatalf("initial dict = %+v", first)
+	}
+
+	if ok, err := w.Reload(); ok || err != nil {
+		t.Fatalf("Reload without changes = %v, %v", ok, err)
+	}
+
+	write(a, `{"words": ["kafka", "FastAPI"]}`, now.Add(time.Minute))
+	if ok, err := w.Reload(); !ok || err != nil {
+		t.Fatalf("Reload after edit = %v, %v", ok, err)
+	}
+	if got := w.Dict(); len(got.Words) != 2 {
+		t.Fatalf("reloaded Words = %v", got.Words)
+	}
+	if len(first.Words) != 1 {
+		t.Fatal("old snapshot was modified in place")
+	}
+
+	write(a, `{broken`, now.Add(2*time.Minute))
+	if _, err := w.Reload(); err == nil {
+		t.Fatal("Reload should fail on a broken file")
+	}
+	if got := w.Dict(); len(got.Words) != 2 {
+		t.Fatalf("last good dict lost: %v", got.Words)
+	}
+	if ok, err := w.Reload(); ok || err != nil {
+		t.Fatalf("Reload of the unchanged broken file = %v, %v; want it skipped", ok, err)
+	}
+
+	write(a, `{broken again`, now.Add(3*time.Minute))
+	if _, err := w.Reload(); err == nil {
+		t.Fatal("Reload should retry a broken file once it changes")
+	}
+	write(a, `{"words": ["kafka", "FastAPI", "gRPC"]}`, now.Add(4*time.Minute))
+	if ok, err := w.Reload(); !ok || err != nil {
+		t.Fatalf("Reload after the fix = %v, %v", ok, err)
+	}
+	if got := w.Dict(); len(got.Words) != 3 {
+		t.Fatalf("fixed dict Words = %v", got.Words)
+	}
+}
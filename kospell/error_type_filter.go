@@ -1,9 +1,8 @@
 package kospell
 
 import (
-	"sort"
+	"fmt"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/Alfex4936/kospell/internal/model"
 	"github.com/Alfex4936/kospell/internal/util"
@@ -14,6 +13,7 @@ const (
 	errorTypeSpacing     = "spacing"
 	errorTypeStandard    = "standard"
 	errorTypeStatistical = "statistical"
+	errorTypeStyle       = "style" // user dictionary replace/banned rules
 	errorTypeUnknown     = "unknown"
 )
 
@@ -21,6 +21,7 @@ func defaultAllowedErrorTypes() map[string]struct{} {
 	return map[string]struct{}{
 		errorTypeSpelling: {},
 		errorTypeSpacing:  {},
+		errorTypeStyle:    {},
 	}
 }
 
@@ -42,6 +43,12 @@ func normalizeErrorTypes(types []string) (map[string]struct{}, []string) {
 	return set, invalid
 }
 
+// NormalizeErrorType maps an error type name or alias (e.g. "맞춤법",
+// "wrong_spacing") to its canonical name.
+func NormalizeErrorType(raw string) (string, bool) {
+	return normalizeErrorType(raw)
+}
+
 func normalizeErrorType(raw string) (string, bool) {
 	s := strings.ToLower(strings.TrimSpace(raw))
 	s = strings.ReplaceAll(s, "_", "")
@@ -57,6 +64,8 @@ func normalizeErrorType(raw string) (string, bool) {
 		return errorTypeStandard, true
 	case "statistical", "statisticalcorrection", "통계적교정", "통계교정":
 		return errorTypeStatistical, true
+	case "style", "dict", "dictionary", "사용자사전", "스타일":
+		return errorTypeStyle, true
 	case "unknown", "기타":
 		return errorTypeUnknown, true
 	default:
@@ -64,6 +73,36 @@ func normalizeErrorType(raw string) (string, bool) {
 	}
 }
 
+// FilterByErrorTypes classifies every correction in res, sets its ErrorType
+// and drops the ones whose type is not listed in types. An empty types uses
+// the server default (spelling, spacing, style). dict, if non-nil, is
+// re-applied to the rebuilt Corrected text.
+func FilterByErrorTypes(res *model.Result, types []string, dict *Dict) error {
+	allowed := defaultAllowedErrorTypes()
+	if len(types) > 0 {
+		var invalid []string
+		allowed, invalid = normalizeErrorTypes(types)
+		if len(invalid) > 0 {
+			return fmt.Errorf("invalid error types: %v", invalid)
+		}
+	}
+	filterResultByErrorTypes(res, allowed, dict)
+	return nil
+}
+
+// ClassifyErrorTypes sets the ErrorType of every correction in res and
+// keeps them all, for callers that report every finding.
+func ClassifyErrorTypes(res *model.Result) {
+	if res == nil {
+		return
+	}
+	for _, c := range res.Corrections {
+		for i := range c.Items {
+			c.Items[i].ErrorType = classifyErrorType(&c.Items[i])
+		}
+	}
+}
+
 func filterResultByErrorTypes(res *model.Result, allowed map[string]struct{}, dict *Dict) {
 	if res == nil || len(allowed) == 0 {
 		return
@@ -76,12 +115,18 @@ func filterResultByErrorTypes(res *model.Result, allowed map[string]struct{}, di
 		kept := c.Items[:0]
 		for i := range c.Items {
 			t := classifyErrorType(&c.Items[i])
-			if _, ok := allowed[t]; ok {
-				item := c.Items[i]
-				item.ErrorType = t
-				if normalizeSuggestionSet(&item) {
-					kept = append(kept, item)
-				}
+			if _, ok := allowed[t]; !ok {
+				continue
+			}
+			if t == errorTypeStyle {
+				// User dictionary rules are reported with or without a suggestion.
+				kept = append(kept, c.Items[i])
+				continue
+			}
+			item := c.Items[i]
+			item.ErrorType = t
+			if normalizeSuggestionSet(&item) {
+				kept = append(kept, item)
 			}
 		}
 		c.Items = kept
@@ -93,8 +138,8 @@ func filterResultByErrorTypes(res *model.Result, allowed map[string]struct{}, di
 
 	res.Corrections = newCorrs
 	res.ErrorCount = totalErrors
-	res.Corrected = applyCorrectionsFromChunks(res.Original, res.Corrections)
-	if dict != nil && len(dict.Words) > 0 {
+	applyEdits(res)
+	if !dict.isEmpty() {
 		res.Corrected = canonicalizeByDictWords(res.Corrected, dict)
 	}
 	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
@@ -133,6 +178,10 @@ func normalizeSuggestionSet(item *model.Correction) bool {
 }
 
 func classifyErrorType(item *model.Correction) string {
+	if item.ErrorType == errorTypeStyle {
+		return errorTypeStyle
+	}
+
 	help := strings.TrimSpace(strings.ToLower(item.Help))
 
 	switch {
@@ -158,73 +207,3 @@ func looksLikeSpacingChange(origin, suggest string) bool {
 	suggestCanon := strings.Join(strings.Fields(suggest), "")
 	return originCanon != "" && originCanon == suggestCanon && origin != suggest
 }
-
-type chunkReplacement struct {
-	start int
-	end   int
-	text  string
-}
-
-// applyCorrectionsFromChunks rebuilds corrected text from original + chunk-local corrections.
-func applyCorrectionsFromChunks(original string, chunks []model.Chunk) string {
-	if len(chunks) == 0 {
-		return original
-	}
-
-	ordered := make([]model.Chunk, 0, len(chunks))
-	ordered = append(ordered, chunks...)
-	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Idx < ordered[j].Idx })
-
-	var reps []chunkReplacement
-	searchFrom := 0 // byte index in original
-
-	for _, ch := range ordered {
-		if ch.Input == "" {
-			continue
-		}
-
-		pos := -1
-		if searchFrom >= 0 && searchFrom <= len(original) {
-			if rel := strings.Index(original[searchFrom:], ch.Input); rel >= 0 {
-				pos = searchFrom + rel
-			}
-		}
-		if pos < 0 {
-			pos = strings.Index(original, ch.Input)
-		}
-		if pos < 0 {
-			continue
-		}
-
-		baseRune := utf8.RuneCountInString(original[:pos])
-		for _, item := range ch.Items {
-			if len(item.Suggest) == 0 {
-				continue
-			}
-			reps = append(reps, chunkReplacement{
-				start: baseRune + item.Start,
-				end:   baseRune + item.End,
-				text:  item.Suggest[0],
-			})
-		}
-
-		searchFrom = pos + len(ch.Input)
-	}
-
-	if len(reps) == 0 {
-		return original
-	}
-
-	sort.Slice(reps, func(i, j int) bool { return reps[i].start > reps[j].start })
-	runes := []rune(original)
-
-	for _, r := range reps {
-		if r.start < 0 || r.end < r.start || r.end > len(runes) {
-			continue
-		}
-		repl := []rune(r.text)
-		runes = append(runes[:r.start], append(repl, runes[r.end:]...)...)
-	}
-
-	return string(runes)
-}
@@ -24,8 +24,8 @@ func TestNormalizeErrorTypes(t *testing.T) {
 
 func TestDefaultAllowedErrorTypes(t *testing.T) {
 	set := defaultAllowedErrorTypes()
-	if len(set) != 2 {
-		t.Fatalf("len(default set) = %d, want 2", len(set))
+	if len(set) != 3 {
+		t.Fatalf("len(default set) = %d, want 3", len(set))
 	}
 	if _, ok := set[errorTypeSpelling]; !ok {
 		t.Fatalf("missing %q", errorTypeSpelling)
@@ -33,6 +33,9 @@ func TestDefaultAllowedErrorTypes(t *testing.T) {
 	if _, ok := set[errorTypeSpacing]; !ok {
 		t.Fatalf("missing %q", errorTypeSpacing)
 	}
+	if _, ok := set[errorTypeStyle]; !ok {
+		t.Fatalf("missing %q", errorTypeStyle)
+	}
 }
 
 func TestFilterResultByErrorTypes_OnlySpacing(t *testing.T) {
@@ -182,3 +185,25 @@ func TestFilterResultByErrorTypes_SetsErrorTypeOnResponseItem(t *testing.T) {
 		t.Fatalf("ErrorType = %q, want %q", got, errorTypeSpelling)
 	}
 }
+
+func TestClassifyErrorTypes_KeepsEverything(t *testing.T) {
+	original := "됬습니다 표준어아닌단어"
+	res := &model.Result{
+		Original:   original,
+		ErrorCount: 2,
+		Corrections: []model.Chunk{{Input: original, Items: []model.Correction{
+			{Start: 0, End: 4, Origin: "됬습니다", Suggest: []string{"됐습니다"}, Help: "맞춤법 오류"},
+			{Start: 5, End: 12, Origin: "표준어아닌단어", Suggest: []string{"표준어아닌단어"}, Help: "표준어 의심"},
+		}}},
+	}
+
+	ClassifyErrorTypes(res)
+
+	items := res.Corrections[0].Items
+	if res.ErrorCount != 2 || len(items) != 2 {
+		t.Fatalf("ErrorCount/items = %d/%d, want 2/2", res.ErrorCount, len(items))
+	}
+	if items[0].ErrorType != errorTypeSpelling || items[1].ErrorType != errorTypeStandard {
+		t.Fatalf("types = %q/%q, want spelling/standard", items[0].ErrorType, items[1].ErrorType)
+	}
+}
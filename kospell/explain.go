@@ -0,0 +1,68 @@
+package kospell
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	internalllm "github.com/Alfex4936/kospell/internal/llm"
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+// minHelpRunes is the shortest help, tags aside, that Explain leaves alone.
+// hanspell's class names ("맞춤법 오류") are well below it.
+const minHelpRunes = 15
+
+var helpTag = regexp.MustCompile(`<[^>]*>`)
+
+// needsHelp reports whether help is empty or too short to explain anything.
+func needsHelp(help string) bool {
+	text := strings.TrimSpace(helpTag.ReplaceAllString(help, ""))
+	return utf8.RuneCountInString(text) < minHelpRunes
+}
+
+// Explain fills the Help of res's corrections whose help is empty or too
+// short, as hunspell's and hanspell's are, with c's explanation of the rule
+// and an example. Corrections are keyed by origin and first suggestion and
+// sent in one prompt; c caches the answers, so repeated pairs cost nothing.
+// Run it after FilterByErrorTypes, which classifies by the original help.
+//
+// The number of corrections filled goes to res.Meta.Explained and the
+// tokens spent to res.Meta.Usage. On error res keeps the help it had.
+func Explain(ctx context.Context, res *model.Result, c *internalllm.Checker) error {
+	var pairs []internalllm.Pair
+	for _, ch := range res.Corrections {
+		for _, item := range ch.Items {
+			if len(item.Suggest) > 0 && needsHelp(item.Help) {
+				pairs = append(pairs, internalllm.Pair{Origin: item.Origin, Suggest: item.Suggest[0]})
+			}
+		}
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	helps, usage, err := c.Explain(ctx, pairs)
+	addUsage(res, usage)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for ci := range res.Corrections {
+		items := res.Corrections[ci].Items
+		for i := range items {
+			if len(items[i].Suggest) == 0 || !needsHelp(items[i].Help) {
+				continue
+			}
+			if help, ok := helps[internalllm.Pair{Origin: items[i].Origin, Suggest: items[i].Suggest[0]}]; ok {
+				items[i].Help = help
+				n++
+			}
+		}
+	}
+	if n > 0 {
+		meta(res).Explained += n
+	}
+	return nil
+}
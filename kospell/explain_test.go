@@ -0,0 +1,100 @@
+package kospell
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	internalllm "github.com/Alfex4936/kospell/internal/llm"
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+// fakeExplainServer explains every pair it is sent as "<origin> 설명".
+func fakeExplainServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return fakeLLM(t, func(user string) string {
+		var in []struct {
+			ID     int    `json:"id"`
+			Origin string `json:"origin"`
+		}
+		if err := json.Unmarshal([]byte(user[strings.Index(user, "["):]), &in); err != nil {
+			t.Errorf("prompt %q: %v", user, err)
+		}
+		type explanation struct {
+			ID   int    `json:"id"`
+			Help string `json:"help"`
+		}
+		var out struct {
+			Explanations []explanation `json:"explanations"`
+		}
+		for _, p := range in {
+			out.Explanations = append(out.Explanations, explanation{p.ID, p.Origin + " 설명"})
+		}
+		content, _ := json.Marshal(out)
+		return string(content)
+	})
+}
+
+func TestExplain(t *testing.T) {
+	srv := fakeExplainServer(t)
+	defer srv.Close()
+
+	long := "어간 '되-'에 '-었-'이 붙은 '되었-'은 '됐-'으로 줄여 씁니다."
+	res := &model.Result{Corrections: []model.Chunk{{Items: []model.Correction{
+		{Origin: "됬다", Suggest: []string{"됐다"}, Help: "맞춤법 오류"},
+		{Origin: "안되요", Suggest: []string{"안돼요"}},
+		{Origin: "됬어", Suggest: []string{"됐어"}, Help: long},
+		{Origin: "됬다", Suggest: []string{"됐다"}, Help: "<b>맞춤법</b>"},
+	}}}}
+
+	if err := Explain(context.Background(), res, internalllm.New("key", "", srv.URL)); err != nil {
+		t.Fatal(err)
+	}
+	var helps []string
+	for _, item := range res.Corrections[0].Items {
+		helps = append(helps, item.Help)
+	}
+	want := []string{"됬다 설명", "안되요 설명", long, "됬다 설명"}
+	if strings.Join(helps, "|") != strings.Join(want, "|") {
+		t.Errorf("help = %q\nwant %q", helps, want)
+	}
+	if res.Meta == nil || res.Meta.Explained != 3 || res.Meta.Usage == nil || res.Meta.Usage.TotalTokens != 15 {
+		t.Errorf("Meta = %+v", res.Meta)
+	}
+}
+
+func TestCheckSpellHandler_ExplainRejectedForOpenAI(t *testing.T) {
+	savedChecker, savedStages := LLMChecker, LLMStages
+	defer func() { LLMChecker, LLMStages = savedChecker, savedStages }()
+	LLMChecker = internalllm.New("key", "", "http://127.0.0.1:0")
+	LLMStages = LLMChecker
+
+	rec := httptest.NewRecorder()
+	CheckSpellHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell",
+		strings.NewReader(`{"text": "됬다", "backend": "openai", "explain": true}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestCheckSpellHandler_StagesDoNotEnableOpenAI(t *testing.T) {
+	var calls atomic.Int32
+	srv := fakeChatServer(t, &calls)
+	defer srv.Close()
+
+	// A nara server started with -llm-explain / -llm-verify.
+	savedMode, savedChecker, savedStages := Mode, LLMChecker, LLMStages
+	defer func() { Mode, LLMChecker, LLMStages = savedMode, savedChecker, savedStages }()
+	Mode, LLMChecker, LLMStages = "nara", nil, internalllm.New("key", "", srv.URL)
+
+	rec := httptest.NewRecorder()
+	CheckSpellHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell",
+		strings.NewReader(`{"text": "일이 됬다", "backend": "openai"}`)))
+	if rec.Code != http.StatusInternalServerError || calls.Load() != 0 {
+		t.Fatalf("status = %d after %d LLM calls, want 500 without calling the LLM", rec.Code, calls.Load())
+	}
+}
@@ -0,0 +1,231 @@
+package kospell
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Feedback decisions.
+const (
+	FeedbackAccept = "accept"
+	FeedbackReject = "reject"
+)
+
+// Default thresholds for FeedbackReportOptions.
+const (
+	DefaultMinRejects    = 3
+	DefaultMinRejectRate = 0.8
+)
+
+// errInvalidFeedback wraps every Feedback validation failure.
+var errInvalidFeedback = errors.New("feedback")
+
+// Feedback is one editor decision on a suggestion.
+type Feedback struct {
+	Origin   string    `json:"origin"`
+	Suggest  string    `json:"suggest"`
+	Decision string    `json:"decision"` // accept | reject
+	Time     time.Time `json:"time,omitempty"`
+}
+
+func (f *Feedback) validate() error {
+	f.Origin = strings.TrimSpace(f.Origin)
+	f.Decision = strings.ToLower(strings.TrimSpace(f.Decision))
+	if f.Origin == "" {
+		return fmt.Errorf("%w: empty \"origin\"", errInvalidFeedback)
+	}
+	if f.Decision != FeedbackAccept && f.Decision != FeedbackReject {
+		return fmt.Errorf("%w: decision must be %q or %q, got %q", errInvalidFeedback, FeedbackAccept, FeedbackReject, f.Decision)
+	}
+	return nil
+}
+
+// FeedbackLog records decisions as JSON lines appended to a local file, so
+// the history can be audited, merged or trimmed with ordinary tools.
+type FeedbackLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// OpenFeedbackLog returns a log backed by path. The file (and its directory)
+// is created on the first Record.
+func OpenFeedbackLog(path string) *FeedbackLog {
+	return &FeedbackLog{path: path}
+}
+
+// Path returns the backing file.
+func (l *FeedbackLog) Path() string { return l.path }
+
+// Record validates and appends decisions. Entries without a time are
+// stamped with the current time. Nothing is written if any entry is invalid.
+func (l *FeedbackLog) Record(entries ...Feedback) error {
+	now := time.Now().UTC()
+	var buf []byte
+	for i := range entries {
+		if err := entries[i].validate(); err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
+		if entries[i].Time.IsZero() {
+			entries[i].Time = now
+		}
+		line, err := json.Marshal(entries[i])
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if dir := filepath.Dir(l.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries reads every recorded decision. A missing file means no entries;
+// malformed lines are skipped.
+func (l *FeedbackLog) Entries() ([]Feedback, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []Feedback
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var fb Feedback
+		if json.Unmarshal(sc.Bytes(), &fb) != nil || fb.validate() != nil {
+			continue
+		}
+		out = append(out, fb)
+	}
+	return out, sc.Err()
+}
+
+// FeedbackReportOptions sets when a term counts as a dictionary candidate.
+// Zero values use DefaultMinRejects and DefaultMinRejectRate.
+type FeedbackReportOptions struct {
+	MinRejects    int     `json:"min_rejects,omitempty"`
+	MinRejectRate float64 `json:"min_reject_rate,omitempty"`
+}
+
+// DictCandidate is a term whose suggestions are mostly rejected.
+type DictCandidate struct {
+	Origin     string   `json:"origin"`
+	Accepted   int      `json:"accepted"`
+	Rejected   int      `json:"rejected"`
+	RejectRate float64  `json:"rejectRate"`
+	Suggest    []string `json:"suggest"` // rejected suggestions, most frequent first
+}
+
+// FeedbackReport groups entries by origin and returns the candidates that
+// meet opts, most rejected first.
+func FeedbackReport(entries []Feedback, opts FeedbackReportOptions) []DictCandidate {
+	if opts.MinRejects <= 0 {
+		opts.MinRejects = DefaultMinRejects
+	}
+	if opts.MinRejectRate <= 0 {
+		opts.MinRejectRate = DefaultMinRejectRate
+	}
+
+	type tally struct {
+		accepted, rejected int
+		suggest            map[string]int
+	}
+	byOrigin := map[string]*tally{}
+	for _, fb := range entries {
+		t := byOrigin[fb.Origin]
+		if t == nil {
+			t = &tally{suggest: map[string]int{}}
+			byOrigin[fb.Origin] = t
+		}
+		if fb.Decision == FeedbackAccept {
+			t.accepted++
+			continue
+		}
+		t.rejected++
+		if fb.Suggest != "" {
+			t.suggest[fb.Suggest]++
+		}
+	}
+
+	var out []DictCandidate
+	for origin, t := range byOrigin {
+		rate := float64(t.rejected) / float64(t.accepted+t.rejected)
+		if t.rejected < opts.MinRejects || rate < opts.MinRejectRate {
+			continue
+		}
+		suggest := make([]string, 0, len(t.suggest))
+		for s := range t.suggest {
+			suggest = append(suggest, s)
+		}
+		sort.Slice(suggest, func(i, j int) bool {
+			if t.suggest[suggest[i]] != t.suggest[suggest[j]] {
+				return t.suggest[suggest[i]] > t.suggest[suggest[j]]
+			}
+			return suggest[i] < suggest[j]
+		})
+		out = append(out, DictCandidate{
+			Origin:     origin,
+			Accepted:   t.accepted,
+			Rejected:   t.rejected,
+			RejectRate: rate,
+			Suggest:    suggest,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Rejected != out[j].Rejected {
+			return out[i].Rejected > out[j].Rejected
+		}
+		return out[i].Origin < out[j].Origin
+	})
+	return out
+}
+
+// PromoteCandidates adds the candidates' origins to the named dictionary in
+// store as protected words and returns the updated Dict. With no candidates
+// nothing is written.
+func PromoteCandidates(store *DictStore, name string, cands []DictCandidate) (*Dict, error) {
+	if len(cands) == 0 {
+		if !reDictName.MatchString(name) {
+			return nil, ErrDictName
+		}
+		d, _ := store.Get(name)
+		return d, nil
+	}
+	words := make([]string, len(cands))
+	for i, c := range cands {
+		words[i] = c.Origin
+	}
+	return store.AddWords(name, words...)
+}
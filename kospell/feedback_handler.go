@@ -0,0 +1,155 @@
+package kospell
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// DefaultFeedback is the log used by /v1/feedback. nil disables feedback.
+var DefaultFeedback *FeedbackLog
+
+// feedbackRequest accepts either a single decision or {"items": [...]}.
+type feedbackRequest struct {
+	Feedback
+	Items []Feedback `json:"items,omitempty"`
+}
+
+type promoteRequest struct {
+	FeedbackReportOptions
+	Dict    string   `json:"dict"`
+	Origins []string `json:"origins,omitempty"` // promote only these candidates (optional)
+}
+
+// FeedbackHandler handles POST /v1/feedback.
+func FeedbackHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !feedbackEnabled(w) {
+		return
+	}
+
+	var req feedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	items := req.Items
+	if len(items) == 0 {
+		items = []Feedback{req.Feedback}
+	}
+	if err := DefaultFeedback.Record(items...); err != nil {
+		writeFeedbackError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"recorded": len(items)})
+}
+
+// FeedbackReportHandler handles GET /v1/feedback/report?min_rejects=&min_reject_rate=.
+func FeedbackReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !feedbackEnabled(w) {
+		return
+	}
+
+	var opts FeedbackReportOptions
+	q := r.URL.Query()
+	if v := q.Get("min_rejects"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid min_rejects: %v", err), http.StatusBadRequest)
+			return
+		}
+		opts.MinRejects = n
+	}
+	if v := q.Get("min_reject_rate"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid min_reject_rate: %v", err), http.StatusBadRequest)
+			return
+		}
+		opts.MinRejectRate = f
+	}
+
+	entries, err := DefaultFeedback.Entries()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	cands := FeedbackReport(entries, opts)
+	if cands == nil {
+		cands = []DictCandidate{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"entries": len(entries), "candidates": cands})
+}
+
+// FeedbackPromoteHandler handles POST /v1/feedback/promote: the current
+// candidates (optionally narrowed to "origins") are added to a named dict.
+func FeedbackPromoteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !feedbackEnabled(w) || !dictStoreEnabled(w) {
+		return
+	}
+
+	var req promoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	entries, err := DefaultFeedback.Entries()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	cands := FeedbackReport(entries, req.FeedbackReportOptions)
+	if len(req.Origins) > 0 {
+		want := make(map[string]struct{}, len(req.Origins))
+		for _, o := range req.Origins {
+			want[o] = struct{}{}
+		}
+		kept := cands[:0]
+		for _, c := range cands {
+			if _, ok := want[c.Origin]; ok {
+				kept = append(kept, c)
+			}
+		}
+		cands = kept
+	}
+
+	promoted := make([]string, len(cands))
+	for i, c := range cands {
+		promoted[i] = c.Origin
+	}
+	d, err := PromoteCandidates(Dicts, req.Dict, cands)
+	if err != nil {
+		writeDictError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"promoted": promoted, "dict": d})
+}
+
+func feedbackEnabled(w http.ResponseWriter) bool {
+	if DefaultFeedback == nil {
+		http.Error(w, "feedback is disabled (start the server with -feedback-file)", http.StatusNotImplemented)
+		return false
+	}
+	return true
+}
+
+func writeFeedbackError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errInvalidFeedback) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,103 @@
+package kospell
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFeedbackReport(t *testing.T) {
+	var entries []Feedback
+	add := func(origin, suggest, decision string, n int) {
+		for range n {
+			entries = append(entries, Feedback{Origin: origin, Suggest: suggest, Decision: decision})
+		}
+	}
+	add("목제솜틀기", "목제 솜틀기", FeedbackReject, 3)
+	add("목제솜틀기", "목재솜틀기", FeedbackReject, 1)
+	add("kafka", "카프카", FeedbackReject, 3)
+	add("kafka", "카프카", FeedbackAccept, 2) // 60% rejected
+	add("됬다", "됐다", FeedbackAccept, 5)
+	add("FastAPI", "패스트API", FeedbackReject, 2)
+
+	got := FeedbackReport(entries, FeedbackReportOptions{})
+	if len(got) != 1 || got[0].Origin != "목제솜틀기" || got[0].Rejected != 4 {
+		t.Fatalf("FeedbackReport() = %+v", got)
+	}
+	if got[0].Suggest[0] != "목제 솜틀기" {
+		t.Fatalf("suggestions not ordered by frequency: %q", got[0].Suggest)
+	}
+
+	got = FeedbackReport(entries, FeedbackReportOptions{MinRejects: 2, MinRejectRate: 0.5})
+	if len(got) != 3 {
+		t.Fatalf("relaxed FeedbackReport() = %+v, want 3 candidates", got)
+	}
+}
+
+func TestFeedbackLog_RecordAndEntries(t *testing.T) {
+	log := OpenFeedbackLog(filepath.Join(t.TempDir(), "sub", "feedback.jsonl"))
+	if entries, err := log.Entries(); err != nil || len(entries) != 0 {
+		t.Fatalf("Entries() on missing file = %v, %v", entries, err)
+	}
+	if err := log.Record(Feedback{Origin: "a", Decision: "REJECT"}, Feedback{Origin: "b", Decision: "accept"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Record(Feedback{Origin: "c", Decision: "maybe"}); err == nil {
+		t.Fatal("Record should reject an unknown decision")
+	}
+	entries, err := log.Entries()
+	if err != nil || len(entries) != 2 || entries[0].Decision != FeedbackReject || entries[0].Time.IsZero() {
+		t.Fatalf("Entries() = %+v, %v", entries, err)
+	}
+}
+
+func TestFeedbackHandlers(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenDictStore(filepath.Join(dir, "dicts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevDicts, prevFeedback := Dicts, DefaultFeedback
+	Dicts, DefaultFeedback = store, OpenFeedbackLog(filepath.Join(dir, "feedback.jsonl"))
+	t.Cleanup(func() { Dicts, DefaultFeedback = prevDicts, prevFeedback })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/feedback", FeedbackHandler)
+	mux.HandleFunc("/v1/feedback/report", FeedbackReportHandler)
+	mux.HandleFunc("/v1/feedback/promote", FeedbackPromoteHandler)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/v1/feedback", `{"origin": "목제솜틀기", "suggest": "목제 솜틀기", "decision": "reject"}`); rec.Code != http.StatusOK {
+		t.Fatalf("POST single = %d %s", rec.Code, rec.Body)
+	}
+	batch := `{"items": [
+		{"origin": "목제솜틀기", "suggest": "목제 솜틀기", "decision": "reject"},
+		{"origin": "목제솜틀기", "suggest": "목제 솜틀기", "decision": "reject"},
+		{"origin": "됬다", "suggest": "됐다", "decision": "accept"}
+	]}`
+	if rec := do(http.MethodPost, "/v1/feedback", batch); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"recorded": 3`) {
+		t.Fatalf("POST batch = %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(http.MethodPost, "/v1/feedback", `{"origin": "x", "decision": "skip"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("POST invalid = %d, want 400", rec.Code)
+	}
+
+	rec := do(http.MethodGet, "/v1/feedback/report", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"origin": "목제솜틀기"`) || strings.Contains(rec.Body.String(), "됬다") {
+		t.Fatalf("GET report = %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := do(http.MethodPost, "/v1/feedback/promote", `{"dict": "brand"}`); rec.Code != http.StatusOK {
+		t.Fatalf("POST promote = %d %s", rec.Code, rec.Body)
+	}
+	if d, ok := store.Get("brand"); !ok || len(d.Words) != 1 || d.Words[0] != "목제솜틀기" {
+		t.Fatalf("promoted dict = %+v", d)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"unicode/utf8"
 
+	"github.com/Alfex4936/kospell/internal/chunk"
 	internalhanspell "github.com/Alfex4936/kospell/internal/hanspell"
 	"github.com/Alfex4936/kospell/internal/model"
 	"github.com/Alfex4936/kospell/internal/util"
@@ -45,7 +46,8 @@ func CheckHanspell(ctx context.Context, text string, c *internalhanspell.Checker
 		return nil, errors.New("hanspell checker is nil")
 	}
 
-	parts := splitHanspellChunks(text)
+	pieces := HanspellChunking.Split(text)
+	parts := pieceTexts(pieces)
 	out := make([]hanspellChunkResult, len(parts))
 
 	sem := make(chan struct{}, cap(make([]byte, 0, runtime.GOMAXPROCS(0))))
@@ -67,17 +69,11 @@ func CheckHanspell(ctx context.Context, text string, c *internalhanspell.Checker
 				return
 			}
 
-			items := buildHanspellCorrections(p, raw.OriginHTML, raw.HTML)
-			corrected := raw.Corrected
-			if corrected == "" {
-				corrected = applyCorrections(p, items)
-			}
-
 			out[i] = hanspellChunkResult{
 				idx:       i,
 				input:     p,
-				corrected: corrected,
-				items:     items,
+				corrected: raw.Corrected,
+				items:     buildHanspellCorrections(p, raw.OriginHTML, raw.HTML),
 			}
 		}()
 	}
@@ -92,79 +88,51 @@ func CheckHanspell(ctx context.Context, text string, c *internalhanspell.Checker
 		ChunkCount: len(parts),
 	}
 
+	starts := chunk.RuneStarts(text, pieces)
 	corrParts := make([]string, len(out))
+	naverCorrected := true
 	for i, cr := range out {
 		corrParts[i] = cr.corrected
-		res.ErrorCount += len(cr.items)
+		naverCorrected = naverCorrected && cr.corrected != ""
 		if len(cr.items) > 0 {
 			res.Corrections = append(res.Corrections, model.Chunk{
-				Idx:   cr.idx,
-				Input: cr.input,
-				Items: cr.items,
+				Idx:    cr.idx,
+				Input:  cr.input,
+				Offset: starts[i],
+				Items:  cr.items,
 			})
 		}
 	}
 
-	res.Corrected = strings.Join(corrParts, "")
+	// Items are validated either way; Naver's own corrected text is preferred
+	// when every chunk has one.
+	applyEdits(res)
+	if naverCorrected {
+		res.Corrected = strings.Join(corrParts, "")
+	}
 	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
 	return res, nil
 }
 
-// CheckHanspellWithDict is like CheckHanspell but filters words listed in dict.
+// CheckHanspellWithDict is like CheckHanspell but filters words and patterns
+// listed in dict and reports dict's replace/banned entries.
 func CheckHanspellWithDict(ctx context.Context, text string, c *internalhanspell.Checker, dict *Dict) (*model.Result, error) {
 	res, err := CheckHanspell(ctx, text, c)
-	if err != nil || dict == nil || len(dict.Words) == 0 {
+	if err != nil || dict.isEmpty() {
 		return res, err
 	}
 	filterByDict(res, dict)
 
-	parts := splitHanspellChunks(res.Original)
-	itemsByIdx := make(map[int][]model.Correction, len(res.Corrections))
-	for _, ch := range res.Corrections {
-		itemsByIdx[ch.Idx] = ch.Items
-	}
-
-	corrParts := make([]string, len(parts))
-	for i, p := range parts {
-		corrParts[i] = applyCorrections(p, itemsByIdx[i])
-	}
-	res.Corrected = strings.Join(corrParts, "")
+	applyDictRules(res, HanspellChunking.Split(res.Original), dict)
+	suppressDictPatterns(res, dict)
+	applyEdits(res)
 	res.Corrected = canonicalizeByDictWords(res.Corrected, dict)
 	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
 	return res, nil
 }
 
-func splitHanspellChunks(text string) []string {
-	if text == "" {
-		return []string{""}
-	}
-	runes := []rune(text)
-	if len(runes) <= hanspellMaxRunesPerChunk {
-		return []string{text}
-	}
-
-	parts := make([]string, 0, (len(runes)+hanspellMaxRunesPerChunk-1)/hanspellMaxRunesPerChunk)
-	for start := 0; start < len(runes); {
-		end := start + hanspellMaxRunesPerChunk
-		if end >= len(runes) {
-			parts = append(parts, string(runes[start:]))
-			break
-		}
-
-		cut := end
-		for i := end; i > start+hanspellMaxRunesPerChunk/2; i-- {
-			if runes[i-1] == ' ' || runes[i-1] == '\n' || runes[i-1] == '\t' {
-				cut = i
-				break
-			}
-		}
-
-		parts = append(parts, string(runes[start:cut]))
-		start = cut
-	}
-	return parts
-}
-
 func buildHanspellCorrections(originalText, originHTML, correctedHTML string) []model.Correction {
 	origins := extractOriginSegments(originHTML)
 	if len(origins) == 0 {
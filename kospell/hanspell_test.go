@@ -57,7 +57,7 @@ func TestBuildHanspellCorrections_HelpByClass(t *testing.T) {
 func TestSplitHanspellChunks_Rejoin(t *testing.T) {
 	// 350+ runes
 	text := strings.Repeat("가나다라마바사 ", 40)
-	parts := splitHanspellChunks(text)
+	parts := pieceTexts(HanspellChunking.Split(text))
 	if len(parts) < 2 {
 		t.Fatalf("len(parts) = %d, want >= 2", len(parts))
 	}
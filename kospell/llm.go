@@ -2,8 +2,14 @@ package kospell
 
 import (
 	"context"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
 	"unicode/utf8"
 
+	"github.com/Alfex4936/kospell/internal/chunk"
+	"github.com/Alfex4936/kospell/internal/edit"
 	internalllm "github.com/Alfex4936/kospell/internal/llm"
 	"github.com/Alfex4936/kospell/internal/model"
 	"github.com/Alfex4936/kospell/internal/util"
@@ -11,85 +17,240 @@ import (
 
 // CheckLLM checks text using the LLM backend.
 // protectedWords are passed to the LLM prompt as 고유명사 (not flagged as errors).
+//
+// Text longer than LLMChunking allows is sent in several prompts of whole
+// sentences, in parallel (bounded by GOMAXPROCS). Each prompt after the first
+// repeats the overlap context before its own text; corrections inside that
+// context are left to the previous prompt.
 func CheckLLM(ctx context.Context, text string, c *internalllm.Checker, protectedWords []string) (*model.Result, error) {
-	raw, err := c.Check(ctx, text, protectedWords)
-	if err != nil {
-		return nil, err
+	return CheckLLMStream(ctx, text, c, protectedWords, nil)
+}
+
+// CheckLLMStream is CheckLLM, calling fn with each correction as soon as
+// the model has written it; see CorrectionFunc. fn may be nil.
+func CheckLLMStream(ctx context.Context, text string, c *internalllm.Checker, protectedWords []string, fn CorrectionFunc) (*model.Result, error) {
+	pieces := LLMChunking.Split(text)
+	emit := pieceEmitters(text, pieces, fn)
+	if len(pieces) == 1 {
+		raw, err := c.CheckStream(ctx, text, protectedWords, emit[0])
+		if err != nil {
+			return nil, err
+		}
+		res := llmToResult(raw, text, 0)
+		model.Locate(res)
+		return res, nil
+	}
+
+	raws := make([]*internalllm.Response, len(pieces))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for i, p := range pieces {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			raw, err := c.CheckStream(ctx, p.Text, protectedWords, emit[i])
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			raws[i] = raw
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return mergeLLMPieces(text, pieces, raws), nil
+}
+
+// mergeLLMPieces combines per-piece responses into one result whose chunks
+// are the pieces' own text, with offsets relative to it. Corrections, drops
+// and repairs inside a piece's overlap context are left to the previous
+// piece; Meta adds up the token usage of every prompt, context included.
+func mergeLLMPieces(text string, pieces []chunk.Piece, raws []*internalllm.Response) *model.Result {
+	res := &model.Result{
+		Original:   text,
+		CharCount:  utf8.RuneCountInString(text),
+		ChunkCount: len(pieces),
+		Meta:       &model.Meta{},
+	}
+	starts := chunk.RuneStarts(text, pieces)
+	for i, p := range pieces {
+		addUsage(res, raws[i].Usage)
+		skip := utf8.RuneCountInString(p.Text[:p.Context])
+		part := llmToResult(raws[i], p.Text, skip)
+		res.Meta.RepairedOffsets += part.Meta.RepairedOffsets
+		res.Meta.DroppedOrigins += part.Meta.DroppedOrigins
+		for _, d := range part.Dropped {
+			d.Idx, d.Start, d.End = i, d.Start-skip, d.End-skip
+			if d.Start < 0 {
+				continue // in the overlap context
+			}
+			res.Dropped = append(res.Dropped, d)
+		}
+
+		var items []model.Correction
+		for _, c := range part.Corrections {
+			for _, item := range c.Items {
+				item.Start += c.Offset - skip
+				item.End += c.Offset - skip
+				if item.Start < 0 {
+					continue // in the overlap context
+				}
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			res.Corrections = append(res.Corrections, model.Chunk{Idx: i, Input: p.Own(), Offset: starts[i], Items: items})
+			res.ErrorCount += len(items)
+		}
+	}
+	applyEdits(res)
+	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
+	return res
+}
+
+// meta returns res.Meta, creating it for backends that set none.
+func meta(res *model.Result) *model.Meta {
+	if res.Meta == nil {
+		res.Meta = &model.Meta{}
+	}
+	return res.Meta
+}
+
+// addUsage adds u to res.Meta.Usage. A zero u, from a provider that
+// reports none, leaves Usage nil.
+func addUsage(res *model.Result, u internalllm.Usage) {
+	if u == (internalllm.Usage{}) {
+		return
+	}
+	m := meta(res)
+	if m.Usage == nil {
+		m.Usage = &model.Usage{}
 	}
-	return llmToResult(raw, text), nil
+	m.Usage.Add(model.Usage(u))
 }
 
-// CheckLLMWithDict is like CheckLLM but passes dict.Words as protected words
-// to the LLM prompt, so they are never flagged.
+// CheckLLMWithDict is like CheckLLM but passes dict.Words (and literal
+// pattern words) as protected words to the LLM prompt, so they are never
+// flagged, drops corrections overlapping dict patterns, and reports dict's
+// replace/banned entries.
 func CheckLLMWithDict(ctx context.Context, text string, c *internalllm.Checker, dict *Dict) (*model.Result, error) {
+	return CheckLLMWithDictStream(ctx, text, c, dict, nil)
+}
+
+// CheckLLMWithDictStream is CheckLLMWithDict, calling fn with each
+// correction as the model writes it. Streamed corrections have not been
+// through the dictionary yet; the returned result has.
+func CheckLLMWithDictStream(ctx context.Context, text string, c *internalllm.Checker, dict *Dict, fn CorrectionFunc) (*model.Result, error) {
 	var protected []string
 	if dict != nil {
-		protected = dict.Words
+		protected = append(protected, dict.Words...)
+		for _, p := range dict.Patterns {
+			if p.Word != "" {
+				protected = append(protected, p.Word)
+			}
+		}
 	}
-	res, err := CheckLLM(ctx, text, c, protected)
+	res, err := CheckLLMStream(ctx, text, c, protected, fn)
 	if err != nil {
 		return nil, err
 	}
-	if dict != nil && len(dict.Words) > 0 {
-		res.Corrected = canonicalizeByDictWords(res.Corrected, dict)
-		res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	if dict.isEmpty() {
+		return res, nil
 	}
+	added := applyDictRules(res, LLMChunking.Split(res.Original), dict)
+	if suppressed := suppressDictPatterns(res, dict); added || suppressed {
+		applyEdits(res)
+	}
+	res.Corrected = canonicalizeByDictWords(res.Corrected, dict)
+	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
 	return res, nil
 }
 
-// llmToResult converts the LLM response into model.Result,
+// llmToResult converts the LLM response for originalText into model.Result,
 // filling in computed fields (distances, editDistance, charCount, …).
-func llmToResult(raw *internalllm.Response, originalText string) *model.Result {
-	original := raw.Original
-	if original == "" {
-		original = originalText
-	}
-	corrected := raw.Corrected
-	if corrected == "" {
-		corrected = original
+//
+// The model's start/end offsets are only a hint: each origin is looked up in
+// originalText and the occurrence nearest to the claimed position wins.
+// Corrections whose origin does not occur are dropped. Both counts go to
+// Meta, and all corrections end up in one chunk covering the whole text.
+// Corrected is rebuilt from the kept corrections.
+//
+// Only corrections at or after rune offset from are counted in Meta; those
+// before it are a chunked prompt's overlap context, which the previous
+// prompt reports. Pass 0 for a whole text.
+func llmToResult(raw *internalllm.Response, originalText string, from int) *model.Result {
+	res := &model.Result{
+		Original:   originalText,
+		CharCount:  utf8.RuneCountInString(originalText),
+		ChunkCount: 1,
+		Meta:       &model.Meta{},
 	}
+	addUsage(res, raw.Usage)
+	doc := []rune(originalText)
 
-	var chunks []model.Chunk
-	totalErrors := 0
-
+	var items []model.Correction
+	searchFrom := 0 // byte index in originalText
 	for _, c := range raw.Corrections {
-		items := make([]model.Correction, 0, len(c.Items))
+		// The model echoes each chunk's input; where it can be found, it
+		// anchors the claimed offsets.
+		offset := 0
+		if k := strings.Index(originalText[searchFrom:], c.Input); c.Input != "" && k >= 0 {
+			offset = utf8.RuneCountInString(originalText[:searchFrom+k])
+			searchFrom += k + len(c.Input)
+		} else if k := strings.Index(originalText, c.Input); c.Input != "" && k >= 0 {
+			offset = utf8.RuneCountInString(originalText[:k])
+		}
+
 		for _, item := range c.Items {
 			dists := make([]int, len(item.Suggest))
 			for i, s := range item.Suggest {
 				dists[i] = util.Levenshtein(item.Origin, s)
 			}
-			items = append(items, model.Correction{
-				Start:     item.Start,
-				End:       item.End,
+			corr := model.Correction{
+				Start:     offset + item.Start,
+				End:       offset + item.End,
 				Origin:    item.Origin,
 				Suggest:   item.Suggest,
 				Distances: dists,
 				Help:      item.Help,
-			})
-		}
-		totalErrors += len(items)
-		if len(items) > 0 {
-			chunks = append(chunks, model.Chunk{
-				Idx:   c.Idx,
-				Input: c.Input,
-				Items: items,
-			})
-		}
-	}
+			}
 
-	chunkCount := len(raw.Corrections)
-	if chunkCount == 0 {
-		chunkCount = 1
+			origin := []rune(item.Origin)
+			at := edit.Nearest(doc, origin, corr.Start)
+			if at < 0 {
+				if corr.Start >= from {
+					res.Meta.DroppedOrigins++
+				}
+				res.Dropped = append(res.Dropped, model.Dropped{Idx: c.Idx, Correction: corr, Reason: string(edit.OriginMismatch)})
+				continue
+			}
+			if at != corr.Start || corr.End != at+len(origin) {
+				if at >= from {
+					res.Meta.RepairedOffsets++
+				}
+				corr.Start, corr.End = at, at+len(origin)
+			}
+			items = append(items, corr)
+		}
 	}
 
-	return &model.Result{
-		Original:     original,
-		Corrected:    corrected,
-		EditDistance: util.Levenshtein(original, corrected),
-		CharCount:    utf8.RuneCountInString(original),
-		ChunkCount:   chunkCount,
-		ErrorCount:   totalErrors,
-		Corrections:  chunks,
+	if len(items) > 0 {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Start < items[j].Start })
+		res.Corrections = []model.Chunk{{Idx: 0, Input: originalText, Items: items}}
 	}
+	applyEdits(res)
+	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	return res
 }
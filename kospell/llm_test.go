@@ -0,0 +1,248 @@
+package kospell
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"unicode/utf8"
+
+	internalllm "github.com/Alfex4936/kospell/internal/llm"
+)
+
+// fakeLLM serves chat completions, answering each request with
+// reply(last user message) as the message content and 15 tokens of usage.
+func fakeLLM(t testing.TB, reply func(user string) string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct{ Content string } `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) == 0 {
+			t.Errorf("decode request: %v (%d messages)", err, len(req.Messages))
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		content := reply(req.Messages[len(req.Messages)-1].Content)
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]string{"content": content}}},
+			"usage":   map[string]int{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15},
+		})
+	}))
+}
+
+// fakeChatServer answers chat completions by flagging every "됬다" in the
+// prompt's input text one rune too late (so its offsets get repaired), each
+// followed by a "됬어" that is not in the text (so it gets dropped),
+// reporting 15 tokens per call.
+func fakeChatServer(t testing.TB, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return fakeLLM(t, func(user string) string {
+		calls.Add(1)
+		input := user[strings.Index(user, "입력:\n")+len("입력:\n"):]
+
+		resp := internalllm.Response{Original: input, Corrected: strings.ReplaceAll(input, "됬다", "됐다")}
+		chunk := internalllm.Chunk{Input: input}
+		for off := 0; ; {
+			k := strings.Index(input[off:], "됬다")
+			if k < 0 {
+				break
+			}
+			start := utf8.RuneCountInString(input[:off+k])
+			chunk.Items = append(chunk.Items,
+				internalllm.Correction{Start: start + 1, End: start + 3, Origin: "됬다", Suggest: []string{"됐다"}},
+				internalllm.Correction{Start: start, End: start + 2, Origin: "됬어", Suggest: []string{"됐어"}},
+			)
+			off += k + len("됬다")
+		}
+		resp.Corrections = []internalllm.Chunk{chunk}
+
+		content, _ := json.Marshal(resp)
+		return string(content)
+	})
+}
+
+func TestCheckLLM_Chunked(t *testing.T) {
+	var calls atomic.Int32
+	srv := fakeChatServer(t, &calls)
+	defer srv.Close()
+
+	saved := LLMChunking
+	defer func() { LLMChunking = saved }()
+	LLMChunking.Size, LLMChunking.Overlap = 20, 8
+
+	text := strings.Repeat("일이 잘 됬다. 정말 좋다. ", 6) + "끝."
+	res, err := CheckLLM(context.Background(), text, internalllm.New("key", "", srv.URL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := int(calls.Load()); n < 2 || res.ChunkCount != n {
+		t.Fatalf("calls = %d, ChunkCount = %d; want several prompts", n, res.ChunkCount)
+	}
+	if u := res.Meta.Usage; u == nil || u.TotalTokens != 15*int(calls.Load()) {
+		t.Errorf("Meta.Usage = %+v, want 15 tokens per prompt", u)
+	}
+	if res.ErrorCount != 6 {
+		t.Errorf("ErrorCount = %d, want 6 (overlap context must not repeat findings)", res.ErrorCount)
+	}
+	if m := res.Meta; m.RepairedOffsets != 6 || m.DroppedOrigins != 6 || len(res.Dropped) != 6 {
+		t.Errorf("repaired %d, dropped %d (%d listed); want 6 each, overlap context not repeated", m.RepairedOffsets, m.DroppedOrigins, len(res.Dropped))
+	}
+	for _, d := range res.Dropped {
+		if d.Start < 0 || d.Origin != "됬어" {
+			t.Errorf("dropped %+v, want an own-text 됬어", d)
+		}
+	}
+	if want := strings.ReplaceAll(text, "됬다", "됐다"); res.Corrected != want {
+		t.Errorf("Corrected = %q\nwant %q", res.Corrected, want)
+	}
+	for _, c := range res.Corrections {
+		runes := []rune(c.Input)
+		for _, item := range c.Items {
+			if got := string(runes[item.Start:item.End]); got != item.Origin {
+				t.Errorf("chunk %d: span %d-%d is %q, want %q", c.Idx, item.Start, item.End, got, item.Origin)
+			}
+		}
+	}
+}
+
+func TestLLMToResult_RepairsOffsets(t *testing.T) {
+	text := "됬다. 그래도 됬다. 정말 안되요."
+	raw := &internalllm.Response{Corrections: []internalllm.Chunk{{Input: text, Items: []internalllm.Correction{
+		{Start: 8, End: 10, Origin: "됬다", Suggest: []string{"됐다"}},    // correct
+		{Start: 1, End: 3, Origin: "됬다", Suggest: []string{"됐다"}},     // off by one: nearest is 0
+		{Start: 30, End: 33, Origin: "안되요", Suggest: []string{"안돼요"}}, // past the end
+		{Start: 4, End: 6, Origin: "그래서", Suggest: []string{"그러므로"}},  // not in the text
+	}}}}
+
+	res := llmToResult(raw, text, 0)
+	if res.Meta == nil || res.Meta.RepairedOffsets != 2 || res.Meta.DroppedOrigins != 1 {
+		t.Fatalf("Meta = %+v, want 2 repaired, 1 dropped", res.Meta)
+	}
+	if len(res.Dropped) != 1 || res.Dropped[0].Origin != "그래서" {
+		t.Errorf("Dropped = %+v", res.Dropped)
+	}
+	if want := "됐다. 그래도 됐다. 정말 안돼요."; res.Corrected != want {
+		t.Errorf("Corrected = %q, want %q", res.Corrected, want)
+	}
+	runes := []rune(text)
+	for _, item := range res.Corrections[0].Items {
+		if got := string(runes[item.Start:item.End]); got != item.Origin {
+			t.Errorf("span [%d,%d) = %q, want %q", item.Start, item.End, got, item.Origin)
+		}
+	}
+}
+
+func TestCheckSpellHandler_Prompt(t *testing.T) {
+	var seen atomic.Value
+	srv := fakeLLM(t, func(user string) string {
+		seen.Store(user)
+		return `{"original": "", "corrected": "", "corrections": []}`
+	})
+	defer srv.Close()
+
+	house, err := internalllm.ParsePrompt("house", "[사내 규칙]\n입력:\n{{.Text}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedChecker, savedPrompts := LLMChecker, LLMPrompts
+	defer func() { LLMChecker, LLMPrompts = savedChecker, savedPrompts }()
+	LLMChecker = internalllm.New("key", "", srv.URL)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		CheckSpellHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell", strings.NewReader(body)))
+		return rec
+	}
+
+	LLMPrompts = nil
+	if rec := post(`{"text": "됬다", "backend": "openai", "prompt": "house"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("prompt without -llm-prompts: status = %d, want 400", rec.Code)
+	}
+
+	LLMPrompts = internalllm.Prompts{"house": house}
+	if rec := post(`{"text": "됬다", "backend": "openai", "prompt": "house"}`); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := seen.Load(); got != "[사내 규칙]\n입력:\n됬다" {
+		t.Errorf("prompt sent = %q", got)
+	}
+	if rec := post(`{"text": "됬다", "backend": "openai"}`); rec.Code != http.StatusOK || seen.Load() != "입력:\n됬다" {
+		t.Errorf("default prompt: status %d, sent %q", rec.Code, seen.Load())
+	}
+	if rec := post(`{"text": "됬다", "backend": "openai", "prompt": "legal"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown prompt: status = %d, want 400", rec.Code)
+	}
+	if rec := post(`{"text": "됬다", "backend": "nara", "prompt": "house"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("prompt on nara: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestCheckSpellHandler_Budget(t *testing.T) {
+	var calls atomic.Int32
+	srv := fakeChatServer(t, &calls)
+	defer srv.Close()
+
+	savedChecker, savedFallback := LLMChecker, LLMBudgetFallback
+	defer func() { LLMChecker, LLMBudgetFallback = savedChecker, savedFallback }()
+	LLMChecker = internalllm.New("key", "", srv.URL)
+	LLMChecker.Meter().SetDailyBudget(20)
+	LLMBudgetFallback = ""
+
+	post := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		CheckSpellHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell", strings.NewReader(`{"text": "일이 됬다", "backend": "openai"}`)))
+		return rec
+	}
+	for i := range 2 {
+		if rec := post(); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d: %s", i, rec.Code, rec.Body)
+		}
+	}
+	if rec := post(); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("over budget: status = %d, want 429", rec.Code)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("API calls = %d, want 2", n)
+	}
+
+	rec := httptest.NewRecorder()
+	UsageHandler(rec, httptest.NewRequest(http.MethodGet, "/v1/usage", nil))
+	var snap internalllm.MeterSnapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snap); err != nil || snap.Total.TotalTokens != 30 || snap.DayTokens != 30 {
+		t.Fatalf("usage = %s (%v)", rec.Body, err)
+	}
+}
+
+func TestCheckSpellHandler_BudgetFallbackMidCheck(t *testing.T) {
+	var calls atomic.Int32
+	srv := fakeChatServer(t, &calls)
+	defer srv.Close()
+
+	savedChecker, savedFallback, savedChunking := LLMChecker, LLMBudgetFallback, LLMChunking
+	defer func() { LLMChecker, LLMBudgetFallback, LLMChunking = savedChecker, savedFallback, savedChunking }()
+	LLMChecker = internalllm.New("key", "", srv.URL)
+	LLMChecker.Meter().SetDailyBudget(10) // the first prompt (15 tokens) spends it
+	LLMChunking.Size, LLMChunking.Overlap = 20, 8
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1)) // one prompt at a time
+	// hunspell is not running here, so the fallback fails with its own error
+	// instead of the 429 the budget would give.
+	LLMBudgetFallback = "hunspell"
+
+	body := `{"text": "` + strings.Repeat("일이 잘 됬다. 정말 좋다. ", 6) + `", "backend": "openai"}`
+	rec := httptest.NewRecorder()
+	CheckSpellHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell", strings.NewReader(body)))
+	if calls.Load() != 1 {
+		t.Fatalf("API calls = %d, want the budget spent by the first", calls.Load())
+	}
+	if got := rec.Header().Get("X-Kospell-Backend"); got != "hunspell" || rec.Code == http.StatusTooManyRequests {
+		t.Fatalf("status %d, backend %q: %s; want the fallback to run", rec.Code, got, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "hunspell mode") {
+		t.Errorf("body = %s, want the fallback's error", rec.Body)
+	}
+}
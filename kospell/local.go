@@ -4,48 +4,57 @@ import (
 	"context"
 	"unicode/utf8"
 
+	"github.com/Alfex4936/kospell/internal/chunk"
 	"github.com/Alfex4936/kospell/internal/local"
 	"github.com/Alfex4936/kospell/internal/model"
 	"github.com/Alfex4936/kospell/internal/util"
 )
 
-// CheckLocal checks text using the local hunspell backend.
+// CheckLocal checks text using the local hunspell backend, one piece of
+// HunspellChunking at a time.
 func CheckLocal(ctx context.Context, text string, h *local.Hunspell) (*model.Result, error) {
-	items, err := h.CheckText(text)
-	if err != nil {
-		return nil, err
-	}
-
+	pieces := HunspellChunking.Split(text)
 	res := &model.Result{
 		Original:   text,
 		CharCount:  utf8.RuneCountInString(text),
-		ChunkCount: 1,
-		ErrorCount: len(items),
+		ChunkCount: len(pieces),
 	}
-	if len(items) > 0 {
-		res.Corrections = []model.Chunk{{Idx: 0, Input: text, Items: items}}
+
+	starts := chunk.RuneStarts(text, pieces)
+	for i, p := range pieces {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		items, err := h.CheckText(p.Text)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) > 0 {
+			res.Corrections = append(res.Corrections, model.Chunk{Idx: i, Input: p.Text, Offset: starts[i], Items: items})
+		}
 	}
 
-	res.Corrected = applyCorrections(text, items)
+	applyEdits(res)
 	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
 	return res, nil
 }
 
-// CheckLocalWithDict is like CheckLocal but filters words listed in dict.
+// CheckLocalWithDict is like CheckLocal but filters words and patterns
+// listed in dict and reports dict's replace/banned entries.
 func CheckLocalWithDict(ctx context.Context, text string, h *local.Hunspell, dict *Dict) (*model.Result, error) {
 	res, err := CheckLocal(ctx, text, h)
-	if err != nil || dict == nil || len(dict.Words) == 0 {
+	if err != nil || dict.isEmpty() {
 		return res, err
 	}
 	filterByDict(res, dict)
+	applyDictRules(res, HunspellChunking.Split(text), dict)
+	suppressDictPatterns(res, dict)
 
 	// Recompute corrected after filtering
-	var filtered []model.Correction
-	for _, c := range res.Corrections {
-		filtered = append(filtered, c.Items...)
-	}
-	res.Corrected = applyCorrections(text, filtered)
+	applyEdits(res)
 	res.Corrected = canonicalizeByDictWords(res.Corrected, dict)
 	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
 	return res, nil
 }
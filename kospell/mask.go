@@ -0,0 +1,64 @@
+package kospell
+
+import (
+	"context"
+
+	"github.com/Alfex4936/kospell/internal/mask"
+	"github.com/Alfex4936/kospell/internal/model"
+	"github.com/Alfex4936/kospell/internal/util"
+)
+
+// MaskOptions selects which kinds of spans are hidden from the backend:
+// URLs, e-mail addresses, code spans, file paths, relative paths, hashtags,
+// @mentions and numbers with units.
+type MaskOptions = mask.Options
+
+// DefaultMask is used by the server when a request does not send "mask",
+// and by kospell-cli unless -mask says otherwise: URLs, e-mail addresses,
+// code and @mentions. Numbers and paths stay visible, since backends check
+// the spacing of "3개" or "10시 30분".
+var DefaultMask = mask.Default()
+
+// ParseMaskOptions builds MaskOptions from category names
+// (url, email, code, path, relpath, hashtag, mention, number, all, none).
+func ParseMaskOptions(names []string) (MaskOptions, error) {
+	return mask.Parse(names)
+}
+
+// CheckMasked masks the spans selected by opts, runs check on the masked
+// text (so chunking never sees them) and maps the result back onto text.
+// Corrections that would have edited a masked span are dropped.
+func CheckMasked(ctx context.Context, text string, opts MaskOptions, check func(context.Context, string) (*model.Result, error)) (*model.Result, error) {
+	return CheckMaskedStream(ctx, text, opts, nil, func(ctx context.Context, text string, _ CorrectionFunc) (*model.Result, error) {
+		return check(ctx, text)
+	})
+}
+
+// CheckMaskedStream is CheckMasked for a streaming check: the CorrectionFunc
+// check receives restores each correction onto text before passing it to
+// fn, and drops those that would edit a masked span. fn may be nil.
+func CheckMaskedStream(ctx context.Context, text string, opts MaskOptions, fn CorrectionFunc, check func(context.Context, string, CorrectionFunc) (*model.Result, error)) (*model.Result, error) {
+	m := mask.Apply(text, opts)
+	if len(m.Spans()) == 0 {
+		return check(ctx, text, fn)
+	}
+
+	restored := fn
+	if fn != nil {
+		restored = func(item model.Correction) {
+			if m.RestoreItem(&item) {
+				fn(item)
+			}
+		}
+	}
+	res, err := check(ctx, m.Text, restored)
+	if err != nil {
+		return nil, err
+	}
+	if m.RestoreResult(res) > 0 {
+		applyEdits(res)
+	}
+	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	model.Locate(res)
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package kospell
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func TestCheckMasked_HidesSpansFromBackend(t *testing.T) {
+	in := "메일은 dev@example.com 로 보내 주세요."
+	var sent string
+	check := func(_ context.Context, text string) (*model.Result, error) {
+		sent = text
+		origin := "보내 주세요"
+		start := utf8.RuneCountInString(text[:strings.Index(text, origin)])
+		return &model.Result{
+			Original:   text,
+			Corrected:  strings.Replace(text, origin, "보내주세요", 1),
+			ErrorCount: 1,
+			Corrections: []model.Chunk{{Input: text, Items: []model.Correction{
+				{Start: start, End: start + 6, Origin: origin, Suggest: []string{"보내주세요"}, Distances: []int{1}},
+			}}},
+		}, nil
+	}
+
+	res, err := CheckMasked(context.Background(), in, MaskOptions{Email: true}, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sent, "@") {
+		t.Fatalf("backend saw the e-mail address: %q", sent)
+	}
+	if res.Original != in || res.Corrected != "메일은 dev@example.com 로 보내주세요." {
+		t.Fatalf("got original %q, corrected %q", res.Original, res.Corrected)
+	}
+	item := res.Corrections[0].Items[0]
+	if got := string([]rune(in)[item.Start:item.End]); got != item.Origin {
+		t.Fatalf("offsets point at %q, want %q", got, item.Origin)
+	}
+}
+
+func TestCheckMasked_DefaultKeepsNumberSpacing(t *testing.T) {
+	in := "2024년 3 개를 10시30분에 샀다."
+	// The backend fixes spacing around numbers and counters, which it can
+	// only do when it sees the digits.
+	check := func(_ context.Context, text string) (*model.Result, error) {
+		res := &model.Result{Original: text, Corrected: text}
+		var items []model.Correction
+		for _, fix := range [][2]string{{"3 개를", "3개를"}, {"10시30분에", "10시 30분에"}} {
+			if k := strings.Index(text, fix[0]); k >= 0 {
+				start := utf8.RuneCountInString(text[:k])
+				items = append(items, model.Correction{Start: start, End: start + utf8.RuneCountInString(fix[0]), Origin: fix[0], Suggest: []string{fix[1]}})
+				res.Corrected = strings.Replace(res.Corrected, fix[0], fix[1], 1)
+			}
+		}
+		if len(items) > 0 {
+			res.Corrections = []model.Chunk{{Input: text, Items: items}}
+			res.ErrorCount = len(items)
+		}
+		return res, nil
+	}
+
+	plain, err := CheckMasked(context.Background(), in, MaskOptions{}, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masked, err := CheckMasked(context.Background(), in, DefaultMask, check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.ErrorCount != 2 {
+		t.Fatalf("unmasked ErrorCount = %d, want 2", plain.ErrorCount)
+	}
+	if got, want := mustJSON(t, masked), mustJSON(t, plain); got != want {
+		t.Errorf("DefaultMask result = %s\nwant the unmasked %s", got, want)
+	}
+}
@@ -0,0 +1,14 @@
+package kospell
+
+import "github.com/Alfex4936/kospell/internal/model"
+
+// OffsetOptions selects extra offset units on every correction:
+// startUtf16/endUtf16 for JavaScript and Java strings, startByte/endByte
+// for Go and Rust. Rune offsets (start/end) are always present.
+type OffsetOptions = model.Offsets
+
+// AddOffsets fills the offset units selected by opts on every correction in
+// res. Call it last: corrections added or moved afterwards are not updated.
+func AddOffsets(res *model.Result, opts OffsetOptions) {
+	model.AddOffsets(res, opts)
+}
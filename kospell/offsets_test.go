@@ -0,0 +1,54 @@
+package kospell
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func TestAddOffsets_SupplementaryPlane(t *testing.T) {
+	input := "좋아요👍🏽 됬다 𠀀자 됬다"
+	var items []model.Correction
+	runes := []rune(input)
+	for i := 0; i+2 <= len(runes); i++ {
+		if string(runes[i:i+2]) == "됬다" {
+			items = append(items, model.Correction{Start: i, End: i + 2, Origin: "됬다", Suggest: []string{"됐다"}})
+		}
+	}
+	res := &model.Result{Original: "앞\n" + input, Corrections: []model.Chunk{{Input: input, Offset: 2, Items: items}}}
+	model.Locate(res)
+	AddOffsets(res, OffsetOptions{UTF16: true, Byte: true})
+
+	units := utf16.Encode([]rune(input))
+	docUnits := utf16.Encode([]rune(res.Original))
+	for _, item := range res.Corrections[0].Items {
+		if item.StartByte == nil || item.StartUTF16 == nil || item.Doc.StartUTF16 == nil {
+			t.Fatalf("offsets not set: %+v", item)
+		}
+		if got := input[*item.StartByte:*item.EndByte]; got != "됬다" {
+			t.Errorf("bytes [%d,%d) = %q, want 됬다", *item.StartByte, *item.EndByte, got)
+		}
+		if got := string(utf16.Decode(units[*item.StartUTF16:*item.EndUTF16])); got != "됬다" {
+			t.Errorf("utf16 [%d,%d) = %q, want 됬다", *item.StartUTF16, *item.EndUTF16, got)
+		}
+		if got := string(utf16.Decode(docUnits[*item.Doc.StartUTF16:*item.Doc.EndUTF16])); got != "됬다" {
+			t.Errorf("doc utf16 [%d,%d) = %q, want 됬다", *item.Doc.StartUTF16, *item.Doc.EndUTF16, got)
+		}
+	}
+	// 👍🏽 is two supplementary-plane runes: 4 UTF-16 units for 2 runes.
+	if first := res.Corrections[0].Items[0]; *first.StartUTF16 != first.Start+2 {
+		t.Errorf("startUtf16 = %d, want %d", *first.StartUTF16, first.Start+2)
+	}
+}
+
+func TestAddOffsets_OffByDefault(t *testing.T) {
+	res := &model.Result{Original: "됬다", Corrections: []model.Chunk{{Input: "됬다", Items: []model.Correction{{Start: 0, End: 2}}}}}
+	AddOffsets(res, OffsetOptions{})
+	out, _ := json.Marshal(res)
+	if strings.Contains(string(out), "Utf16") || strings.Contains(string(out), "startByte") {
+		t.Fatalf("offset variants present without options: %s", out)
+	}
+}
@@ -3,8 +3,11 @@ package kospell
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	internalhanspell "github.com/Alfex4936/kospell/internal/hanspell"
@@ -23,28 +26,83 @@ var LocalHunspell *local.Hunspell
 // LLMChecker is the shared LLM client used when Mode == "openai".
 var LLMChecker *internalllm.Checker
 
+// LLMPrompts holds the named prompt templates (-llm-prompts) a request may
+// select with "prompt". nil disables selection.
+var LLMPrompts internalllm.Prompts
+
+// LLMBudgetFallback is the backend ("nara" or "hanspell") that serves
+// openai requests once LLMChecker's daily token budget is spent. Empty
+// rejects them with 429.
+var LLMBudgetFallback string
+
+// LLMStages is the shared LLM client of the explain and verify stages, set
+// in any mode by -llm-explain or -llm-verify. It never serves the openai
+// backend, which needs LLMChecker, though both may be the same client.
+var LLMStages *internalllm.Checker
+
+// LLMExplain has LLMStages write the help of nara, hunspell and hanspell
+// corrections whose help is empty or too short, for requests that do not
+// send "explain".
+var LLMExplain bool
+
+// LLMVerify has LLMStages judge nara, hunspell and hanspell corrections in
+// context and move false positives to the result's suppressed list, for
+// requests that do not send "verify".
+var LLMVerify bool
+
 // HanspellChecker is the shared Naver checker used when Mode == "hanspell".
 var HanspellChecker *internalhanspell.Checker
 
+// DefaultDicts holds the server-wide dictionary files (-user-dict and the
+// dicts listed in .kospell.yaml). Its current snapshot is merged into every
+// request's dictionary. nil means none.
+var DefaultDicts *DictWatcher
+
+// DefaultErrorTypes replaces the built-in error type filter for requests
+// that do not send error_types. Empty means spelling, spacing and style.
+var DefaultErrorTypes []string
+
 // CheckSpellRequest is the HTTP request body for /v1/check-spell
 type CheckSpellRequest struct {
-	Text       string   `json:"text"`                  // 검사할 텍스트 (필수)
-	Backend    string   `json:"backend,omitempty"`     // 백엔드 선택 (선택: nara|hunspell|hanspell|openai)
-	Words      []string `json:"words,omitempty"`       // 인라인 허용 단어 목록 (선택)
-	Dict       *Dict    `json:"dict,omitempty"`        // 사용자 딕셔너리 {"words":[...]} (선택)
-	DictPath   string   `json:"dict_path,omitempty"`   // (deprecated) 딕셔너리 JSON 파일 경로 (서버 로컬)
-	Timeout    int      `json:"timeout,omitempty"`     // 타임아웃 (초, 기본: openai=180, 그 외=8)
-	ErrorTypes []string `json:"error_types,omitempty"` // 교정할 오류 유형 필터 (선택)
+	Text       string        `json:"text"`                  // 검사할 텍스트 (필수)
+	Backend    string        `json:"backend,omitempty"`     // 백엔드 선택 (선택: nara|hunspell|hanspell|openai)
+	Words      []string      `json:"words,omitempty"`       // 인라인 허용 단어 목록 (선택)
+	Dict       *Dict         `json:"dict,omitempty"`        // 사용자 딕셔너리 {"words":[...], "replace":[...], "banned":[...]} (선택)
+	Dicts      []string      `json:"dicts,omitempty"`       // 서버에 저장된 이름 있는 딕셔너리 (선택)
+	DictPath   string        `json:"dict_path,omitempty"`   // (removed) 더 이상 지원하지 않음 — dicts 사용
+	Timeout    int           `json:"timeout,omitempty"`     // 타임아웃 (초, 기본: openai·explain=180, 그 외=8)
+	ErrorTypes []string      `json:"error_types,omitempty"` // 교정할 오류 유형 필터 (선택)
+	Mask       *MaskOptions  `json:"mask,omitempty"`        // 마스킹할 범주 (선택, 생략 시 DefaultMask, null이면 끄기)
+	Offsets    OffsetOptions `json:"offsets,omitempty"`     // 추가 오프셋 단위 {"utf16": true, "byte": true} (선택)
+	Prompt     string        `json:"prompt,omitempty"`      // openai 프롬프트 템플릿 이름 (선택, 생략 시 서버 기본)
+	Explain    *bool         `json:"explain,omitempty"`     // 짧은 도움말을 LLM 설명으로 채우기 (nara|hunspell|hanspell, 생략 시 서버 기본)
+	Verify     *bool         `json:"verify,omitempty"`      // LLM이 문맥으로 오탐을 걸러 suppressed로 옮기기 (nara|hunspell|hanspell, 생략 시 서버 기본)
 }
 
 // CheckSpellHandler handles POST /v1/check-spell requests
 func CheckSpellHandler(w http.ResponseWriter, r *http.Request) {
+	checkSpell(w, r, false)
+}
+
+// CheckSpellStreamHandler handles POST /v1/check-spell/stream: the same
+// request as /v1/check-spell, answered with server-sent events. Each
+// "correction" event carries one correction as soon as the openai backend
+// has written it; the final "result" event carries the same body
+// /v1/check-spell returns, which is authoritative. Other backends send only
+// the result. A failure after the first event is sent as an "error" event.
+func CheckSpellStreamHandler(w http.ResponseWriter, r *http.Request) {
+	checkSpell(w, r, true)
+}
+
+func checkSpell(w http.ResponseWriter, r *http.Request, stream bool) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	var req CheckSpellRequest
+	// Mask starts from the server default so omitted categories keep it.
+	defaultMask := DefaultMask
+	req := CheckSpellRequest{Mask: &defaultMask}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
 		return
@@ -60,10 +118,33 @@ func CheckSpellHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), status)
 		return
 	}
+	if req.Prompt != "" && backend != "openai" {
+		http.Error(w, "prompt applies only to the openai backend", http.StatusBadRequest)
+		return
+	}
+	explain, ok := llmStage(w, "explain", LLMExplain, req.Explain, backend)
+	if !ok {
+		return
+	}
+	verify, ok := llmStage(w, "verify", LLMVerify, req.Verify, backend)
+	if !ok {
+		return
+	}
+	// 일일 토큰 예산을 다 쓰면 대체 백엔드로 보내거나 거절한다.
+	if backend == "openai" && LLMChecker != nil && LLMChecker.Meter().Exceeded() {
+		if LLMBudgetFallback == "" {
+			http.Error(w, internalllm.ErrBudgetExceeded.Error(), http.StatusTooManyRequests)
+			return
+		}
+		backend = LLMBudgetFallback
+		w.Header().Set("X-Kospell-Backend", backend)
+	}
 
-	// 타임아웃 설정 (기본: openai=180초, 기타=8초)
-	defaultTimeout := defaultTimeoutForBackend(backend)
-	timeout := defaultTimeout
+	// 타임아웃 설정 (기본: openai=180초, 기타=8초, explain·verify는 LLM을 부르므로 180초)
+	timeout := defaultTimeoutForBackend(backend)
+	if explain || verify {
+		timeout = defaultTimeoutForBackend(backendOpenAI)
+	}
 	if req.Timeout > 0 {
 		timeout = time.Duration(req.Timeout) * time.Second
 	}
@@ -71,81 +152,251 @@ func CheckSpellHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	// 딕셔너리 구성: words(인라인) + dict(요청 본문) + dict_path(서버 로컬 파일, deprecated) 병합
-	var dict *Dict
-	if len(req.Words) > 0 || (req.Dict != nil && len(req.Dict.Words) > 0) || req.DictPath != "" {
-		dict = NewDict(req.Words...)
-		if req.Dict != nil {
-			dict.Words = append(dict.Words, req.Dict.Words...)
+	if req.DictPath != "" {
+		http.Error(w, "dict_path is no longer supported; use \"dicts\" with /v1/dicts", http.StatusBadRequest)
+		return
+	}
+	if err := req.Dict.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid dict: %v", err), http.StatusBadRequest)
+		return
+	}
+	dict, err := requestDict(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	allowedTypes := defaultAllowedErrorTypes()
+	errorTypes := req.ErrorTypes
+	if len(errorTypes) == 0 {
+		errorTypes = DefaultErrorTypes
+	}
+	if len(errorTypes) > 0 {
+		var invalid []string
+		allowedTypes, invalid = normalizeErrorTypes(errorTypes)
+		if len(invalid) > 0 {
+			http.Error(w, fmt.Sprintf("Invalid error_types: %v", invalid), http.StatusBadRequest)
+			return
 		}
-		if req.DictPath != "" {
-			fileDict, err := LoadDict(req.DictPath)
-			if err != nil {
-				http.Error(w, fmt.Sprintf("Failed to load dictionary: %v", err), http.StatusInternalServerError)
+	}
+
+	check, status, err := backendCheck(backend, dict, req.Prompt)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// URL·이메일·코드 등은 마스킹한 뒤 검사하고 결과 오프셋을 원문 기준으로 되돌린다.
+	var maskOpts MaskOptions
+	if req.Mask != nil {
+		maskOpts = *req.Mask
+	}
+	// 스트리밍: 교정 항목을 최종 결과와 같은 방식(유형 필터, 위치, 오프셋)으로 다듬어 바로 보낸다.
+	var events *sseWriter
+	var onItem CorrectionFunc
+	if stream {
+		events = &sseWriter{w: w}
+		onItem = func(item model.Correction) {
+			one := &model.Result{Original: req.Text, Corrections: []model.Chunk{{Input: req.Text, Items: []model.Correction{item}}}, ErrorCount: 1}
+			filterResultByErrorTypes(one, allowedTypes, nil)
+			if len(one.Corrections) == 0 {
 				return
 			}
-			dict.Words = append(dict.Words, fileDict.Words...)
+			model.Locate(one)
+			AddOffsets(one, req.Offsets)
+			events.send("correction", one.Corrections[0].Items[0])
+		}
+	}
+
+	res, err := CheckMaskedStream(ctx, req.Text, maskOpts, onItem, check)
+	// 청크 검사 도중 예산이 바닥나도 대체 백엔드가 있으면 그쪽으로 다시 검사한다.
+	if errors.Is(err, internalllm.ErrBudgetExceeded) && backend == "openai" && LLMBudgetFallback != "" {
+		backend = LLMBudgetFallback
+		if events == nil || !events.started {
+			w.Header().Set("X-Kospell-Backend", backend)
+		}
+		if check, status, err = backendCheck(backend, dict, ""); err != nil {
+			http.Error(w, err.Error(), status)
+			return
 		}
+		res, err = CheckMaskedStream(ctx, req.Text, maskOpts, onItem, check)
+	}
+	if err != nil {
+		status, msg := http.StatusInternalServerError, fmt.Sprintf("Check failed: %v", err)
+		if errors.Is(err, internalllm.ErrBudgetExceeded) {
+			status, msg = http.StatusTooManyRequests, err.Error()
+		}
+		if events != nil && events.started {
+			events.send("error", map[string]any{"status": status, "error": msg})
+			return
+		}
+		http.Error(w, msg, status)
+		return
 	}
 
-	var res *model.Result
+	filterResultByErrorTypes(res, allowedTypes, dict)
+	// LLM 단계는 부가 기능이라 실패해도 결과는 그대로 보낸다.
+	// 걸러낸 뒤에 설명해야 suppressed 항목에 토큰을 쓰지 않는다.
+	if verify && LLMStages != nil {
+		if err := Verify(ctx, res, LLMStages, dict); err != nil && !errors.Is(err, internalllm.ErrBudgetExceeded) {
+			log.Printf("verify: %v", err)
+		}
+	}
+	if explain && LLMStages != nil {
+		if err := Explain(ctx, res, LLMStages); err != nil && !errors.Is(err, internalllm.ErrBudgetExceeded) {
+			log.Printf("explain: %v", err)
+		}
+	}
+	AddOffsets(res, req.Offsets)
 
+	if events != nil {
+		events.send("result", res)
+		return
+	}
+	// JSON 응답 (HTML 이스케이프 비활성화)
+	w.Header().Set("Content-Type", "application/json")
+	out, _ := util.MarshalNoEscape(res, true)
+	fmt.Fprint(w, string(out))
+}
+
+// backendCheck returns the check for backend, with dict applied when it is
+// non-nil and, for openai, the named prompt template. When the backend
+// cannot run it returns the HTTP status and error to answer with.
+func backendCheck(backend string, dict *Dict, prompt string) (func(context.Context, string, CorrectionFunc) (*model.Result, error), int, error) {
 	switch backend {
 	case "openai":
 		if LLMChecker == nil {
-			http.Error(w, "openai mode: LLM checker not initialized", http.StatusInternalServerError)
-			return
+			return nil, http.StatusInternalServerError, errors.New("openai mode: LLM checker not initialized")
 		}
-		if dict != nil {
-			res, err = CheckLLMWithDict(ctx, req.Text, LLMChecker, dict)
-		} else {
-			res, err = CheckLLM(ctx, req.Text, LLMChecker, nil)
+		checker := LLMChecker
+		if prompt != "" {
+			if LLMPrompts == nil {
+				return nil, http.StatusBadRequest, errors.New("prompt templates are disabled (start the server with -llm-prompts)")
+			}
+			p, err := LLMPrompts.Lookup(prompt)
+			if err != nil {
+				return nil, http.StatusBadRequest, err
+			}
+			checker = checker.WithPrompt(p)
 		}
+		return func(ctx context.Context, text string, fn CorrectionFunc) (*model.Result, error) {
+			if dict != nil {
+				return CheckLLMWithDictStream(ctx, text, checker, dict, fn)
+			}
+			return CheckLLMStream(ctx, text, checker, nil, fn)
+		}, 0, nil
 	case "hunspell":
 		if LocalHunspell == nil {
-			http.Error(w, "hunspell mode: checker not initialized", http.StatusInternalServerError)
-			return
-		}
-		if dict != nil {
-			res, err = CheckLocalWithDict(ctx, req.Text, LocalHunspell, dict)
-		} else {
-			res, err = CheckLocal(ctx, req.Text, LocalHunspell)
+			return nil, http.StatusInternalServerError, errors.New("hunspell mode: checker not initialized")
 		}
+		return func(ctx context.Context, text string, _ CorrectionFunc) (*model.Result, error) {
+			if dict != nil {
+				return CheckLocalWithDict(ctx, text, LocalHunspell, dict)
+			}
+			return CheckLocal(ctx, text, LocalHunspell)
+		}, 0, nil
 	case "hanspell", "naver":
 		checker := getHanspellChecker()
-		if dict != nil {
-			res, err = CheckHanspellWithDict(ctx, req.Text, checker, dict)
-		} else {
-			res, err = CheckHanspell(ctx, req.Text, checker)
-		}
+		return func(ctx context.Context, text string, _ CorrectionFunc) (*model.Result, error) {
+			if dict != nil {
+				return CheckHanspellWithDict(ctx, text, checker, dict)
+			}
+			return CheckHanspell(ctx, text, checker)
+		}, 0, nil
 	default: // nara
-		if dict != nil {
-			res, err = CheckWithDict(ctx, req.Text, dict)
-		} else {
-			res, err = Check(ctx, req.Text)
+		return func(ctx context.Context, text string, _ CorrectionFunc) (*model.Result, error) {
+			if dict != nil {
+				return CheckWithDict(ctx, text, dict)
+			}
+			return Check(ctx, text)
+		}, 0, nil
+	}
+}
+
+// requestDict merges the dictionaries of req: the DefaultDicts snapshot,
+// the named dicts, then the inline words and dict. The snapshots are taken
+// once, so a hot reload never changes the dictionary mid-request. Without
+// inline entries the merge is shared across requests (or is the snapshot
+// itself), so the compiled matcher is too. nil means no dictionary.
+func requestDict(req *CheckSpellRequest) (*Dict, error) {
+	defaultDict := DefaultDicts.Dict()
+	var named []*Dict
+	if len(req.Dicts) > 0 {
+		if Dicts == nil {
+			return nil, errors.New("named dictionaries are disabled (start the server with -dict-store)")
 		}
+		var err error
+		if named, err = Dicts.entries(req.Dicts...); err != nil {
+			return nil, err
+		}
+	}
+	shared := defaultDict
+	if len(named) > 0 {
+		shared = sharedMerges.merge(strings.Join(req.Dicts, "\x00"), append([]*Dict{defaultDict}, named...)...)
 	}
+	if len(req.Words) == 0 && req.Dict.isEmpty() {
+		return shared, nil
+	}
+	return MergeDicts(shared, NewDict(req.Words...), req.Dict), nil
+}
 
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Check failed: %v", err), http.StatusInternalServerError)
-		return
+// llmStage resolves whether an optional LLM stage (explain, verify) runs
+// for a request: the request's flag if sent, else the server default. It
+// writes a 400 and returns false when the request turns the stage on for
+// the openai backend or on a server without an LLM.
+func llmStage(w http.ResponseWriter, name string, def bool, requested *bool, backend string) (on, ok bool) {
+	if requested == nil {
+		return def && backend != "openai", true
 	}
+	switch {
+	case *requested && backend == "openai":
+		http.Error(w, name+" applies only to the nara, hunspell and hanspell backends", http.StatusBadRequest)
+		return false, false
+	case *requested && LLMStages == nil:
+		http.Error(w, name+" is disabled (start the server with -llm-"+name+")", http.StatusBadRequest)
+		return false, false
+	}
+	return *requested, true
+}
 
-	allowedTypes := defaultAllowedErrorTypes()
-	if len(req.ErrorTypes) > 0 {
-		var invalid []string
-		allowedTypes, invalid = normalizeErrorTypes(req.ErrorTypes)
-		if len(invalid) > 0 {
-			http.Error(w, fmt.Sprintf("Invalid error_types: %v", invalid), http.StatusBadRequest)
-			return
-		}
+// sseWriter writes server-sent events, sending the response headers with
+// the first one so errors before it can still be plain HTTP errors.
+type sseWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func (s *sseWriter) send(event string, v any) {
+	if !s.started {
+		s.started = true
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
 	}
-	filterResultByErrorTypes(res, allowedTypes, dict)
+	data, _ := util.MarshalNoEscape(v, false)
+	fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, data)
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
 
-	// JSON 응답 (HTML 이스케이프 비활성화)
+// UsageHandler handles GET /v1/usage: the token usage and cost of the
+// openai backend, or else of the LLM stages, since the server started.
+func UsageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	c := LLMChecker
+	if c == nil {
+		c = LLMStages
+	}
+	if c == nil {
+		http.Error(w, "usage is tracked for the openai backend and LLM stages only", http.StatusNotImplemented)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	out, _ := util.MarshalNoEscape(res, true)
-	fmt.Fprint(w, string(out))
+	json.NewEncoder(w).Encode(c.Meter().Snapshot())
 }
 
 // HealthHandler handles GET /health requests
@@ -200,9 +451,15 @@ const openAPISpec = `{
                 "백엔드 지정(hanspell)": {
                   "value": { "text": "안녕 하세요. 저는 한국인 입니다.", "backend": "hanspell" }
                 },
+                "이름 있는 딕셔너리(dicts)": {
+                  "value": { "text": "너는나와 kafka 머고나서", "dicts": ["brand", "tech"] }
+                },
                 "사용자 딕셔너리(dict)": {
                   "value": { "text": "너는나와 kafka 머고나서", "dict": { "words": ["kafka"] } }
                 },
+                "강제 치환·금지어": {
+                  "value": { "text": "일이 잘됬다. 카프카를 썼다.", "dict": { "replace": [{ "from": "카프카", "to": "Kafka" }], "banned": [{ "word": "잘됬다", "suggest": ["잘됐다"], "severity": "warning" }] } }
+                },
                 "오류 유형 제한": {
                   "value": { "text": "안녕 하세요. 저는 한국인 입니다.", "error_types": ["spacing"] }
                 },
@@ -238,8 +495,176 @@ const openAPISpec = `{
               }
             }
           },
-          "400": { "description": "잘못된 요청 (JSON 파싱 오류 등)" },
-          "500": { "description": "서버 오류 (딕셔너리 로드 실패, 외부 API 오류 등)" }
+          "400": { "description": "잘못된 요청 (JSON 파싱 오류, 없는 딕셔너리 이름 등)" },
+          "429": { "description": "openai 일일 토큰 예산 소진 (-llm-budget-fallback 미설정 시)" },
+          "500": { "description": "서버 오류 (외부 API 오류 등)" }
+        }
+      }
+    },
+    "/v1/check-spell/stream": {
+      "post": {
+        "summary": "Check Spell (stream)",
+        "description": "/v1/check-spell 과 같은 요청을 받아 server-sent events로 응답합니다. openai 백엔드는 모델이 교정 항목을 하나 완성할 때마다 correction 이벤트(Correction 객체, 원문 기준 오프셋)를 보내고, 마지막에 result 이벤트로 /v1/check-spell 과 같은 본문을 보냅니다. 스트리밍된 항목은 잠정값이며 result가 최종입니다. 다른 백엔드는 result만 보냅니다. 첫 이벤트 이후의 실패는 error 이벤트({\"status\", \"error\"})로 전달됩니다.",
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": { "$ref": "#/components/schemas/CheckSpellRequest" },
+              "example": { "text": "일이 잘 됬다. 정말 안되요.", "backend": "openai" }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "이벤트 스트림 (event: correction | result | error)",
+            "content": {
+              "text/event-stream": {
+                "schema": { "type": "string" },
+                "example": "event: correction\ndata: {\"start\":5,\"end\":7,\"origin\":\"됬다\",\"suggest\":[\"됐다\"],\"distances\":[1],\"error_type\":\"spelling\"}\n\nevent: result\ndata: {\"original\":\"일이 잘 됬다. 정말 안되요.\", ...}\n\n"
+              }
+            }
+          },
+          "400": { "description": "잘못된 요청 (첫 이벤트 전에 일반 HTTP 오류로 응답)" },
+          "429": { "description": "openai 일일 토큰 예산 소진" },
+          "500": { "description": "서버 오류" }
+        }
+      }
+    },
+    "/v1/dicts": {
+      "get": {
+        "summary": "List Dicts",
+        "description": "서버에 저장된 딕셔너리 이름 목록 (서버를 -dict-store 로 시작해야 함)",
+        "responses": {
+          "200": { "description": "이름 목록", "content": { "application/json": { "example": { "dicts": ["brand", "tech"] } } } },
+          "501": { "description": "딕셔너리 저장소 비활성화" }
+        }
+      }
+    },
+    "/v1/dicts/{name}": {
+      "parameters": [
+        { "name": "name", "in": "path", "required": true, "schema": { "type": "string", "pattern": "^[A-Za-z0-9_-]{1,64}$" } }
+      ],
+      "get": {
+        "summary": "Get Dict",
+        "responses": {
+          "200": { "description": "딕셔너리", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Dict" } } } },
+          "404": { "description": "없는 딕셔너리" }
+        }
+      },
+      "put": {
+        "summary": "Put Dict",
+        "description": "딕셔너리를 생성하거나 통째로 교체합니다.",
+        "requestBody": { "required": true, "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Dict" } } } },
+        "responses": {
+          "200": { "description": "저장된 딕셔너리", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Dict" } } } },
+          "400": { "description": "잘못된 이름 또는 딕셔너리" }
+        }
+      },
+      "delete": {
+        "summary": "Delete Dict",
+        "responses": {
+          "204": { "description": "삭제됨" },
+          "404": { "description": "없는 딕셔너리" }
+        }
+      }
+    },
+    "/v1/dicts/{name}/words": {
+      "parameters": [
+        { "name": "name", "in": "path", "required": true, "schema": { "type": "string" } }
+      ],
+      "post": {
+        "summary": "Add Words",
+        "description": "보호 단어를 추가합니다. 딕셔너리가 없으면 새로 만듭니다.",
+        "requestBody": { "required": true, "content": { "application/json": { "example": { "words": ["kafka"] } } } },
+        "responses": { "200": { "description": "갱신된 딕셔너리", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Dict" } } } } }
+      },
+      "delete": {
+        "summary": "Remove Words",
+        "requestBody": { "required": true, "content": { "application/json": { "example": { "words": ["kafka"] } } } },
+        "responses": {
+          "200": { "description": "갱신된 딕셔너리", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Dict" } } } },
+          "404": { "description": "없는 딕셔너리" }
+        }
+      }
+    },
+    "/v1/feedback": {
+      "post": {
+        "summary": "Record Feedback",
+        "description": "교정 제안에 대한 수락/거절 결정을 기록합니다. 단일 객체 또는 {\"items\": [...]}를 받습니다. 서버를 -feedback-file로 시작해야 합니다 (아니면 501).",
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": { "$ref": "#/components/schemas/Feedback" },
+              "examples": {
+                "single": { "value": { "origin": "목제솜틀기", "suggest": "목제 솜틀기", "decision": "reject" } },
+                "batch":  { "value": { "items": [{ "origin": "kafka", "suggest": "카프카", "decision": "reject" }, { "origin": "됬다", "suggest": "됐다", "decision": "accept" }] } }
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": { "description": "기록된 개수", "content": { "application/json": { "example": { "recorded": 1 } } } },
+          "400": { "description": "잘못된 origin 또는 decision" },
+          "501": { "description": "feedback 비활성화" }
+        }
+      }
+    },
+    "/v1/feedback/report": {
+      "get": {
+        "summary": "Dictionary Candidates",
+        "description": "자주 거절된 용어를 사전 후보로 나열합니다 (거절 많은 순).",
+        "parameters": [
+          { "name": "min_rejects", "in": "query", "schema": { "type": "integer", "default": 3 }, "description": "최소 거절 횟수" },
+          { "name": "min_reject_rate", "in": "query", "schema": { "type": "number", "default": 0.8 }, "description": "최소 거절 비율 (0~1)" }
+        ],
+        "responses": {
+          "200": {
+            "description": "후보 목록",
+            "content": {
+              "application/json": {
+                "example": { "entries": 5, "candidates": [{ "origin": "목제솜틀기", "accepted": 0, "rejected": 4, "rejectRate": 1, "suggest": ["목제 솜틀기"] }] }
+              }
+            }
+          }
+        }
+      }
+    },
+    "/v1/feedback/promote": {
+      "post": {
+        "summary": "Promote Candidates",
+        "description": "현재 후보(또는 그중 origins로 고른 것)를 이름 있는 딕셔너리의 보호 단어로 추가합니다. -dict-store가 필요합니다.",
+        "requestBody": {
+          "required": true,
+          "content": { "application/json": { "example": { "dict": "brand", "min_rejects": 3, "origins": ["목제솜틀기"] } } }
+        },
+        "responses": {
+          "200": { "description": "추가된 용어와 갱신된 딕셔너리", "content": { "application/json": { "example": { "promoted": ["목제솜틀기"], "dict": { "words": ["목제솜틀기"] } } } } },
+          "400": { "description": "잘못된 딕셔너리 이름" },
+          "501": { "description": "feedback 또는 dict store 비활성화" }
+        }
+      }
+    },
+    "/v1/usage": {
+      "get": {
+        "summary": "LLM Usage",
+        "description": "서버 시작 이후 openai 백엔드(openai 모드가 아니면 explain·verify 단계)의 모델별 토큰 사용량과 누적 비용(USD, -llm-prices 기준), 오늘 사용량과 일일 예산을 반환합니다.",
+        "responses": {
+          "200": {
+            "description": "사용량",
+            "content": {
+              "application/json": {
+                "example": {
+                  "models": { "gpt-5-mini": { "promptTokens": 12000, "completionTokens": 3000, "totalTokens": 15000, "cost": 0.009 } },
+                  "total": { "promptTokens": 12000, "completionTokens": 3000, "totalTokens": 15000, "cost": 0.009 },
+                  "day": "2026-10-18",
+                  "dayTokens": 15000,
+                  "dailyBudget": 1000000
+                }
+              }
+            }
+          },
+          "501": { "description": "LLM 비활성화 (openai 모드, -llm-explain, -llm-verify 모두 아님)" }
         }
       }
     },
@@ -269,24 +694,113 @@ const openAPISpec = `{
           "backend":   { "type": "string", "description": "요청별 백엔드 지정 (선택, 미지정 시 서버 기본 MODE 사용)", "enum": ["nara", "hunspell", "hanspell", "openai"], "example": "hanspell" },
           "words":     { "type": "array", "items": { "type": "string" }, "description": "오류에서 제외할 단어 목록 (인라인)", "example": ["kafka", "KoSpell"] },
           "dict":      { "$ref": "#/components/schemas/Dict" },
-          "dict_path": { "type": "string", "description": "(deprecated) 딕셔너리 JSON 파일 경로 (서버 로컬)", "example": "/etc/kospell/dict.json", "deprecated": true },
+          "dicts":     { "type": "array", "items": { "type": "string" }, "description": "/v1/dicts 에 저장된 딕셔너리 이름 목록", "example": ["brand", "tech"] },
           "error_types": {
             "type": "array",
-            "description": "교정할 오류 유형 제한 (선택). 미지정 시 기본값은 [\"spelling\", \"spacing\"]",
+            "description": "교정할 오류 유형 제한 (선택). 미지정 시 기본값은 [\"spelling\", \"spacing\", \"style\"]. 사용자 사전 규칙(style)도 나열해야 보고됩니다",
             "items": {
               "type": "string",
-              "enum": ["spelling", "spacing", "standard", "statistical", "unknown"]
+              "enum": ["spelling", "spacing", "standard", "statistical", "style", "unknown"]
             },
-            "default": ["spelling", "spacing"],
+            "default": ["spelling", "spacing", "style"],
             "example": ["spacing", "spelling"]
           },
-          "timeout":   { "type": "integer", "description": "타임아웃 (초, 기본값: openai=180, 그 외=8)", "example": 8 }
+          "mask": {
+            "type": "object",
+            "nullable": true,
+            "description": "검사 전에 자리표시자로 가릴 범주. 생략한 필드는 서버 기본값(url, email, code, mention)을 따르고, null이면 마스킹하지 않습니다.",
+            "properties": {
+              "url":     { "type": "boolean", "default": true },
+              "email":   { "type": "boolean", "default": true },
+              "code":    { "type": "boolean", "default": true, "description": "백틱 인라인 코드와 펜스 코드 블록" },
+              "path":    { "type": "boolean", "default": false, "description": "/usr/bin, ./a.go, C:\\Temp 같은 경로" },
+              "relpath": { "type": "boolean", "default": false, "description": "cmd/main.go 같은 상대 경로 (and/or, 1/2 도 가려짐)" },
+              "hashtag": { "type": "boolean", "default": false },
+              "mention": { "type": "boolean", "default": true },
+              "number":  { "type": "boolean", "default": false, "description": "숫자와 단위 (10kg, 3.5GB, 100%). 켜면 3개·10시30분 같은 띄어쓰기는 검사하지 못합니다" }
+            },
+            "example": { "path": true }
+          },
+          "offsets": {
+            "type": "object",
+            "description": "start/end(문자 단위) 외에 함께 받을 오프셋 단위. 이모지 등 BMP 밖 문자는 UTF-16 2단위, UTF-8 4바이트로 계산합니다.",
+            "properties": {
+              "utf16": { "type": "boolean", "default": false, "description": "startUtf16/endUtf16 (JavaScript, Java)" },
+              "byte":  { "type": "boolean", "default": false, "description": "startByte/endByte (Go, Rust)" }
+            },
+            "example": { "utf16": true }
+          },
+          "prompt": {
+            "type": "string",
+            "description": "openai 백엔드에서 사용할 프롬프트 템플릿 이름 (-llm-prompts 디렉터리의 <이름>.tmpl). 생략 시 서버 기본(-llm-prompt), default는 내장 프롬프트. 다른 백엔드에 지정하면 400",
+            "example": "house"
+          },
+          "explain": {
+            "type": "boolean",
+            "description": "nara·hunspell·hanspell 교정 중 도움말이 비었거나 짧은 항목을 LLM이 쓴 규칙 설명과 예문으로 채웁니다. 생략 시 서버 기본(-llm-explain). openai 백엔드에 true를 지정하거나 서버에 LLM이 없으면 400",
+            "example": true
+          },
+          "verify": {
+            "type": "boolean",
+            "description": "nara·hunspell·hanspell 교정마다 LLM이 문장 문맥을 보고 실제 오류인지 판단해, 오탐(고유명사·전문 용어 등)을 suppressed로 옮깁니다. 사용자 딕셔너리 규칙은 판단하지 않습니다. 생략 시 서버 기본(-llm-verify). openai 백엔드에 true를 지정하거나 서버에 LLM이 없으면 400",
+            "example": true
+          },
+          "timeout":   { "type": "integer", "description": "타임아웃 (초, 기본값: openai·explain·verify=180, 그 외=8)", "example": 8 }
         }
       },
       "Dict": {
         "type": "object",
         "properties": {
-          "words": { "type": "array", "items": { "type": "string" }, "description": "오류에서 제외할 단어 목록", "example": ["kafka", "KoSpell"] }
+          "words": { "type": "array", "items": { "type": "string" }, "description": "오류에서 제외할 단어 목록", "example": ["kafka", "KoSpell"] },
+          "replace": {
+            "type": "array",
+            "description": "강제 치환 규칙. from이 나오면 항상 to로 교정합니다 (error_type=style).",
+            "items": {
+              "type": "object",
+              "required": ["from", "to"],
+              "properties": {
+                "from":     { "type": "string", "example": "됬" },
+                "to":       { "type": "string", "example": "됐" },
+                "message":  { "type": "string", "description": "오류 설명 (help)" },
+                "severity": { "type": "string", "enum": ["error", "warning", "info"], "default": "error" }
+              }
+            }
+          },
+          "banned": {
+            "type": "array",
+            "description": "사용 금지·지양 표현 (error_type=style).",
+            "items": {
+              "type": "object",
+              "required": ["word"],
+              "properties": {
+                "word":     { "type": "string", "example": "노가다" },
+                "suggest":  { "type": "array", "items": { "type": "string" }, "example": ["막일"] },
+                "message":  { "type": "string", "description": "오류 설명 (help)" },
+                "severity": { "type": "string", "enum": ["error", "warning", "info"], "default": "warning" }
+              }
+            }
+          },
+          "patterns": {
+            "type": "array",
+            "description": "보호 패턴. word 또는 regex 중 하나를 지정하며, 일치 구간과 겹치는 교정은 모든 백엔드에서 제외됩니다.",
+            "items": {
+              "type": "object",
+              "properties": {
+                "word":        { "type": "string", "description": "리터럴 단어", "example": "kafka" },
+                "regex":       { "type": "string", "description": "Go 정규식 (RE2)", "example": "[A-Z]+-\\d+" },
+                "ignore_case": { "type": "boolean", "description": "대소문자 무시", "default": false }
+              }
+            }
+          }
+        }
+      },
+      "Feedback": {
+        "type": "object",
+        "properties": {
+          "origin":   { "type": "string", "description": "검사기가 지적한 원문", "example": "목제솜틀기" },
+          "suggest":  { "type": "string", "description": "수락/거절한 제안", "example": "목제 솜틀기" },
+          "decision": { "type": "string", "enum": ["accept", "reject"] },
+          "items":    { "type": "array", "description": "여러 건을 한 번에 보낼 때", "items": { "type": "object" } }
         }
       },
       "Result": {
@@ -298,27 +812,102 @@ const openAPISpec = `{
           "charCount":    { "type": "integer" },
           "chunkCount":   { "type": "integer" },
           "errorCount":   { "type": "integer" },
-          "corrections":  { "type": "array", "items": { "$ref": "#/components/schemas/Chunk" } }
+          "corrections":  { "type": "array", "items": { "$ref": "#/components/schemas/Chunk" } },
+          "meta": {
+            "type": "object",
+            "description": "openai 백엔드와 LLM 단계(explain, verify)의 후처리 기록",
+            "properties": {
+              "repairedOffsets": { "type": "integer", "description": "origin 위치를 찾아 start/end를 바로잡은 교정 수" },
+              "droppedOrigins":  { "type": "integer", "description": "origin이 본문에 없어 버린 교정 수" },
+              "explained":       { "type": "integer", "description": "explain 단계에서 LLM이 help를 채운 교정 수" },
+              "verified":        { "type": "integer", "description": "verify 단계에서 LLM이 문맥으로 판단한 교정 수" },
+              "usage": {
+                "type": "object",
+                "description": "이 결과에 쓴 LLM 토큰 (재요청 포함)",
+                "properties": {
+                  "promptTokens":     { "type": "integer" },
+                  "completionTokens": { "type": "integer" },
+                  "totalTokens":      { "type": "integer" },
+                  "cost":             { "type": "number", "description": "USD (-llm-prices에 모델 가격이 있을 때)" }
+                }
+              }
+            }
+          },
+          "dropped": {
+            "type": "array",
+            "description": "적용할 수 없어 corrections에서 제외된 교정 (범위 밖, origin 불일치, 다른 교정과 겹침)",
+            "items": {
+              "allOf": [
+                { "$ref": "#/components/schemas/Correction" },
+                {
+                  "type": "object",
+                  "properties": {
+                    "idx":    { "type": "integer", "description": "원래 청크 번호" },
+                    "reason": { "type": "string", "enum": ["out_of_range", "origin_mismatch", "overlap", "masked"] }
+                  }
+                }
+              ]
+            }
+          },
+          "suppressed": {
+            "type": "array",
+            "description": "verify 단계에서 LLM이 오탐으로 판단해 corrections에서 뺀 교정 (감사용)",
+            "items": {
+              "allOf": [
+                { "$ref": "#/components/schemas/Correction" },
+                {
+                  "type": "object",
+                  "properties": {
+                    "idx":    { "type": "integer", "description": "원래 청크 번호" },
+                    "reason": { "type": "string", "description": "LLM이 밝힌 판단 근거" }
+                  }
+                }
+              ]
+            }
+          }
         }
       },
       "Chunk": {
         "type": "object",
         "properties": {
-          "idx":   { "type": "integer" },
-          "input": { "type": "string" },
-          "items": { "type": "array", "items": { "$ref": "#/components/schemas/Correction" } }
+          "idx":    { "type": "integer" },
+          "input":  { "type": "string" },
+          "offset": { "type": "integer", "description": "original에서 input이 시작하는 문자(rune) 위치" },
+          "items":  { "type": "array", "items": { "$ref": "#/components/schemas/Correction" } }
         }
       },
       "Correction": {
         "type": "object",
         "properties": {
-          "start":   { "type": "integer", "description": "오류 시작 위치 (바이트)" },
-          "end":     { "type": "integer", "description": "오류 끝 위치 (바이트)" },
+          "start":   { "type": "integer", "description": "오류 시작 위치 (청크 input 안의 문자(rune) 단위)" },
+          "end":     { "type": "integer", "description": "오류 끝 위치 (청크 input 안의 문자(rune) 단위, 미포함)" },
+          "startUtf16": { "type": "integer", "description": "start의 UTF-16 코드 단위 위치 (요청 offsets.utf16일 때만)" },
+          "endUtf16":   { "type": "integer", "description": "end의 UTF-16 코드 단위 위치 (요청 offsets.utf16일 때만)" },
+          "startByte":  { "type": "integer", "description": "start의 UTF-8 바이트 위치 (요청 offsets.byte일 때만)" },
+          "endByte":    { "type": "integer", "description": "end의 UTF-8 바이트 위치 (요청 offsets.byte일 때만)" },
           "origin":  { "type": "string",  "description": "원본 오류 단어" },
           "suggest":   { "type": "array", "items": { "type": "string" }, "description": "교정 제안 목록" },
           "distances": { "type": "array", "items": { "type": "integer" }, "description": "suggest[i]와 origin 간 Levenshtein 편집거리" },
           "help":      { "type": "string",  "description": "오류 설명" },
-          "error_type": { "type": "string", "description": "오류 유형", "enum": ["spelling", "spacing", "standard", "statistical", "unknown"], "example": "spacing" }
+          "error_type": { "type": "string", "description": "오류 유형 (style: 사용자 사전 규칙)", "enum": ["spelling", "spacing", "standard", "statistical", "style", "unknown"], "example": "spacing" },
+          "severity":   { "type": "string", "description": "심각도 (사용자 사전 규칙만)", "enum": ["error", "warning", "info"] },
+          "doc":        { "$ref": "#/components/schemas/Span" }
+        }
+      },
+      "Span": {
+        "type": "object",
+        "description": "문서(original) 전체 기준 위치 — 청크 위치를 따로 계산할 필요 없음",
+        "properties": {
+          "start":     { "type": "integer", "description": "시작 문자(rune) 위치" },
+          "end":       { "type": "integer", "description": "끝 문자(rune) 위치 (미포함)" },
+          "startByte": { "type": "integer", "description": "시작 UTF-8 바이트 위치" },
+          "endByte":   { "type": "integer", "description": "끝 UTF-8 바이트 위치 (미포함)" },
+          "line":      { "type": "integer", "description": "시작 줄 (1부터)" },
+          "column":    { "type": "integer", "description": "시작 열 (1부터, 문자 단위)" },
+          "endLine":   { "type": "integer" },
+          "endColumn": { "type": "integer", "description": "끝 열 (미포함)" },
+          "startUtf16": { "type": "integer", "description": "시작 UTF-16 코드 단위 위치 (요청 offsets.utf16일 때만)" },
+          "endUtf16":   { "type": "integer", "description": "끝 UTF-16 코드 단위 위치 (요청 offsets.utf16일 때만)" }
         }
       }
     }
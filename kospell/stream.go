@@ -0,0 +1,62 @@
+package kospell
+
+import (
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/chunk"
+	"github.com/Alfex4936/kospell/internal/edit"
+	internalllm "github.com/Alfex4936/kospell/internal/llm"
+	"github.com/Alfex4936/kospell/internal/model"
+	"github.com/Alfex4936/kospell/internal/util"
+)
+
+// Correction is one finding in a Result.
+type Correction = model.Correction
+
+// CorrectionFunc receives corrections while a check is still running. Start
+// and End are rune offsets in the whole checked text, and the origin has
+// been located in it the way the final result locates it. Streamed
+// corrections are provisional: the returned result is authoritative and
+// may drop, merge or move some of them. Calls are never concurrent.
+type CorrectionFunc func(Correction)
+
+// pieceEmitters returns, for each piece, the callback its Checker streams
+// into: it locates the item in the piece, skips items in the overlap
+// context and passes the rest to fn with document offsets. All nil when fn
+// is nil.
+func pieceEmitters(text string, pieces []chunk.Piece, fn CorrectionFunc) []func(internalllm.Correction) {
+	emit := make([]func(internalllm.Correction), len(pieces))
+	if fn == nil {
+		return emit
+	}
+	starts := chunk.RuneStarts(text, pieces)
+	var mu sync.Mutex
+	for i, p := range pieces {
+		doc := []rune(p.Text)
+		skip := utf8.RuneCountInString(p.Text[:p.Context])
+		emit[i] = func(item internalllm.Correction) {
+			origin := []rune(item.Origin)
+			at := edit.Nearest(doc, origin, item.Start)
+			if at < skip {
+				return // not in the text, or left to the previous piece
+			}
+			dists := make([]int, len(item.Suggest))
+			for k, s := range item.Suggest {
+				dists[k] = util.Levenshtein(item.Origin, s)
+			}
+			start := starts[i] + at - skip
+			mu.Lock()
+			defer mu.Unlock()
+			fn(model.Correction{
+				Start:     start,
+				End:       start + len(origin),
+				Origin:    item.Origin,
+				Suggest:   item.Suggest,
+				Distances: dists,
+				Help:      item.Help,
+			})
+		}
+	}
+	return emit
+}
@@ -0,0 +1,137 @@
+package kospell
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	internalllm "github.com/Alfex4936/kospell/internal/llm"
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+func TestCheckLLMStream_Chunked(t *testing.T) {
+	var calls atomic.Int32
+	srv := fakeChatServer(t, &calls)
+	defer srv.Close()
+
+	saved := LLMChunking
+	defer func() { LLMChunking = saved }()
+	LLMChunking.Size, LLMChunking.Overlap = 20, 8
+
+	text := strings.Repeat("일이 잘 됬다. 정말 좋다. ", 6) + "끝."
+	var streamed []model.Correction
+	res, err := CheckLLMStream(context.Background(), text, internalllm.New("key", "", srv.URL), nil, func(item model.Correction) {
+		streamed = append(streamed, item)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	final := make(map[int]bool)
+	for _, c := range res.Corrections {
+		for _, item := range c.Items {
+			final[item.Doc.Start] = true
+		}
+	}
+	if len(streamed) != len(final) {
+		t.Fatalf("streamed %d corrections, result has %d (overlap context must not repeat them)", len(streamed), len(final))
+	}
+	runes := []rune(text)
+	for _, item := range streamed {
+		if got := string(runes[item.Start:item.End]); got != item.Origin || !final[item.Start] {
+			t.Errorf("streamed span [%d,%d) = %q, in result: %v", item.Start, item.End, got, final[item.Start])
+		}
+	}
+}
+
+// sseEvent is one server-sent event.
+type sseEvent struct {
+	name, data string
+}
+
+func readEvents(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var ev sseEvent
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			ev.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			events = append(events, ev)
+			ev = sseEvent{}
+		}
+	}
+	return events
+}
+
+func TestCheckSpellStreamHandler(t *testing.T) {
+	var calls atomic.Int32
+	srv := fakeChatServer(t, &calls)
+	defer srv.Close()
+
+	saved := LLMChecker
+	defer func() { LLMChecker = saved }()
+	LLMChecker = internalllm.New("key", "", srv.URL)
+
+	// The URL is masked, so the correction after it moves in the original.
+	// The fake sends no help, so its corrections classify as unknown.
+	text := "https://example.com/a 일이 됬다"
+	body := `{"text": "` + text + `", "backend": "openai", "offsets": {"byte": true}, "error_types": ["unknown"]}`
+	rec := httptest.NewRecorder()
+	CheckSpellStreamHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell/stream", strings.NewReader(body)))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("status = %d, content type %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+
+	events := readEvents(t, rec.Body.String())
+	if len(events) != 2 || events[0].name != "correction" || events[1].name != "result" {
+		t.Fatalf("events = %+v, want one correction and the result", events)
+	}
+	var item model.Correction
+	if err := json.Unmarshal([]byte(events[0].data), &item); err != nil {
+		t.Fatal(err)
+	}
+	if got := string([]rune(text)[item.Start:item.End]); got != "됬다" || item.Doc == nil || item.StartByte == nil || item.ErrorType == "" {
+		t.Errorf("correction = %s", events[0].data)
+	}
+
+	plain := httptest.NewRecorder()
+	CheckSpellHandler(plain, httptest.NewRequest(http.MethodPost, "/v1/check-spell", strings.NewReader(body)))
+	var want, got model.Result
+	json.Unmarshal(plain.Body.Bytes(), &want)
+	json.Unmarshal([]byte(events[1].data), &got)
+	if w, g := mustJSON(t, want), mustJSON(t, got); w != g {
+		t.Errorf("result event = %s\nwant the /v1/check-spell body %s", g, w)
+	}
+	if len(got.Corrections) != 1 || got.Corrections[0].Items[0].Start != item.Start {
+		t.Errorf("streamed correction at %d does not match the result %+v", item.Start, got.Corrections)
+	}
+}
+
+func TestCheckSpellStreamHandler_ErrorBeforeEvents(t *testing.T) {
+	rec := httptest.NewRecorder()
+	CheckSpellStreamHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell/stream",
+		strings.NewReader(`{"text": "됬다", "error_types": ["bogus"]}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want a plain 400 before any event", rec.Code)
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
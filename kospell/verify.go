@@ -0,0 +1,108 @@
+package kospell
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Alfex4936/kospell/internal/chunk"
+	internalllm "github.com/Alfex4936/kospell/internal/llm"
+	"github.com/Alfex4936/kospell/internal/model"
+	"github.com/Alfex4936/kospell/internal/util"
+)
+
+// Verify asks c whether each correction in res is a real error in its
+// sentence, to weed out the proper nouns and domain terms nara, hanspell
+// and hunspell flag. All corrections go in one prompt; user dictionary
+// rules are never sent. Those judged false positives move from Corrections
+// to res.Suppressed with the model's reason, and Corrected is rebuilt,
+// re-applying dict if it is non-nil.
+//
+// The number of corrections judged goes to res.Meta.Verified and the tokens
+// spent to res.Meta.Usage. On error the corrections are left as they were.
+func Verify(ctx context.Context, res *model.Result, c *internalllm.Checker, dict *Dict) error {
+	doc := []rune(res.Original)
+	sentStarts := []int{0} // rune offset of each sentence, then len(doc)
+	for _, s := range chunk.Sentences(res.Original) {
+		sentStarts = append(sentStarts, sentStarts[len(sentStarts)-1]+utf8.RuneCountInString(s))
+	}
+
+	type ref struct{ ch, item int }
+	var claims []internalllm.Claim
+	var refs []ref
+	for ci, ch := range res.Corrections {
+		for i, item := range ch.Items {
+			start, end := ch.Offset+item.Start, ch.Offset+item.End
+			if item.ErrorType == errorTypeStyle || start < 0 || end > len(doc) || start > end {
+				continue
+			}
+			claims = append(claims, internalllm.Claim{
+				Sentence: markedSentence(doc, sentStarts, start, end),
+				Origin:   item.Origin,
+				Suggest:  item.Suggest,
+				Help:     item.Help,
+			})
+			refs = append(refs, ref{ci, i})
+		}
+	}
+	if len(claims) == 0 {
+		return nil
+	}
+
+	verdicts, usage, err := c.Verify(ctx, claims)
+	addUsage(res, usage)
+	if err != nil {
+		return err
+	}
+	meta(res).Verified += len(claims)
+
+	drop := make(map[ref]string)
+	for k, v := range verdicts {
+		if !v.Keep {
+			drop[refs[k]] = v.Reason
+		}
+	}
+	if len(drop) == 0 {
+		return nil
+	}
+	for ci := range res.Corrections {
+		ch := &res.Corrections[ci]
+		kept := ch.Items[:0]
+		for i, item := range ch.Items {
+			if reason, ok := drop[ref{ci, i}]; ok {
+				res.Suppressed = append(res.Suppressed, model.Dropped{Idx: ch.Idx, Correction: item, Reason: reason})
+				continue
+			}
+			kept = append(kept, item)
+		}
+		ch.Items = kept
+	}
+	applyEdits(res) // also drops the emptied chunks and recounts
+	if !dict.isEmpty() {
+		res.Corrected = canonicalizeByDictWords(res.Corrected, dict)
+	}
+	res.EditDistance = util.Levenshtein(res.Original, res.Corrected)
+	return nil
+}
+
+// markedSentence returns the sentences of doc covering [start, end), with
+// that span wrapped in ⟦ ⟧.
+func markedSentence(doc []rune, sentStarts []int, start, end int) string {
+	from, to := 0, len(doc)
+	for k := 0; k+1 < len(sentStarts); k++ {
+		if sentStarts[k] <= start {
+			from = sentStarts[k]
+		}
+		if sentStarts[k+1] >= end && sentStarts[k+1] > start {
+			to = sentStarts[k+1]
+			break
+		}
+	}
+	var b strings.Builder
+	b.WriteString(string(doc[from:start]))
+	b.WriteString("⟦")
+	b.WriteString(string(doc[start:end]))
+	b.WriteString("⟧")
+	b.WriteString(string(doc[end:to]))
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,88 @@
+package kospell
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	internalllm "github.com/Alfex4936/kospell/internal/llm"
+	"github.com/Alfex4936/kospell/internal/model"
+)
+
+// fakeVerifyServer drops every claim whose origin starts with "카카오" and
+// records the sentences it was sent.
+func fakeVerifyServer(t *testing.T, sentences *[]string) *httptest.Server {
+	t.Helper()
+	return fakeLLM(t, func(user string) string {
+		var in []struct {
+			ID       int    `json:"id"`
+			Sentence string `json:"sentence"`
+			Origin   string `json:"origin"`
+		}
+		if err := json.Unmarshal([]byte(user[strings.Index(user, "["):]), &in); err != nil {
+			t.Errorf("prompt %q: %v", user, err)
+		}
+		type verdict struct {
+			ID     int    `json:"id"`
+			Keep   bool   `json:"keep"`
+			Reason string `json:"reason"`
+		}
+		var out struct {
+			Verdicts []verdict `json:"verdicts"`
+		}
+		for _, c := range in {
+			*sentences = append(*sentences, c.Sentence)
+			keep := !strings.HasPrefix(c.Origin, "카카오")
+			out.Verdicts = append(out.Verdicts, verdict{c.ID, keep, "판단"})
+		}
+		content, _ := json.Marshal(out)
+		return string(content)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	var sentences []string
+	srv := fakeVerifyServer(t, &sentences)
+	defer srv.Close()
+
+	text := "어제 카카오뱅크에 갔다. 일이 잘 됬다."
+	res := &model.Result{Original: text, Corrections: []model.Chunk{{Input: text, Items: []model.Correction{
+		{Start: 3, End: 8, Origin: "카카오뱅크", Suggest: []string{"카카오 뱅크"}},
+		{Start: 18, End: 20, Origin: "됬다", Suggest: []string{"됐다"}},
+		{Start: 0, End: 2, Origin: "어제", Suggest: []string{"어저께"}, ErrorType: errorTypeStyle}, // user rule: not sent
+	}}}}
+	applyEdits(res)
+
+	if err := Verify(context.Background(), res, internalllm.New("key", "", srv.URL), nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"어제 ⟦카카오뱅크⟧에 갔다.", "일이 잘 ⟦됬다⟧."}; strings.Join(sentences, "|") != strings.Join(want, "|") {
+		t.Errorf("sentences = %q, want %q", sentences, want)
+	}
+	if len(res.Suppressed) != 1 || res.Suppressed[0].Origin != "카카오뱅크" || res.Suppressed[0].Reason != "판단" {
+		t.Fatalf("Suppressed = %+v", res.Suppressed)
+	}
+	if res.ErrorCount != 2 || res.Corrected != "어저께 카카오뱅크에 갔다. 일이 잘 됐다." {
+		t.Errorf("ErrorCount = %d, Corrected = %q", res.ErrorCount, res.Corrected)
+	}
+	if res.Meta == nil || res.Meta.Verified != 2 || res.Meta.Usage == nil || res.Meta.Usage.TotalTokens != 15 {
+		t.Errorf("Meta = %+v", res.Meta)
+	}
+}
+
+func TestCheckSpellHandler_VerifyNeedsLLM(t *testing.T) {
+	// The openai backend's client does not serve the stages.
+	savedChecker, savedStages := LLMChecker, LLMStages
+	defer func() { LLMChecker, LLMStages = savedChecker, savedStages }()
+	LLMChecker, LLMStages = internalllm.New("key", "", "http://127.0.0.1:0"), nil
+
+	rec := httptest.NewRecorder()
+	CheckSpellHandler(rec, httptest.NewRequest(http.MethodPost, "/v1/check-spell",
+		strings.NewReader(`{"text": "됬다", "backend": "nara", "verify": true}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}